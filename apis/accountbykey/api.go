@@ -0,0 +1,47 @@
+package accountbykey
+
+import (
+	// Stdlib
+	"encoding/json"
+
+	// RPC
+	"github.com/asuleymanov/rpc/interfaces"
+	"github.com/asuleymanov/rpc/internal/rpc"
+
+	// Vendor
+	"github.com/pkg/errors"
+)
+
+const APIID = "account_by_key_api"
+
+type API struct {
+	caller *rpc.APICaller
+}
+
+//NewAPI creates a new API instance. The numeric API ID is resolved lazily
+//on the first call, so no error is returned when the node does not expose the API.
+func NewAPI(caller interfaces.Caller) (*API, error) {
+	return &API{rpc.NewAPICaller(caller, APIID)}, nil
+}
+
+func (api *API) Raw(method string, params interface{}) (*json.RawMessage, error) {
+	var resp json.RawMessage
+	if err := api.caller.Call(method, params, &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to call %v\n", APIID, method)
+	}
+	return &resp, nil
+}
+
+//get_key_references
+//For every public key passed in, the names of the accounts using the key are returned.
+func (api *API) GetKeyReferences(keys []string) ([][]string, error) {
+	raw, err := api.Raw("get_key_references", [][]string{keys})
+	if err != nil {
+		return nil, err
+	}
+	var resp [][]string
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_key_references response", APIID)
+	}
+	return resp, nil
+}
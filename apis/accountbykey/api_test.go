@@ -0,0 +1,55 @@
+package accountbykey
+
+import (
+	// Stdlib
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+// fakeNode exposes account_by_key_api with ID 5 and records the last call.
+type fakeNode struct {
+	params string
+}
+
+func (node *fakeNode) Call(method string, params, response interface{}) error {
+	args := params.([]interface{})
+	if method != "call" {
+		return fmt.Errorf("unexpected method %v", method)
+	}
+	switch args[1] {
+	case "get_api_by_name":
+		return json.Unmarshal([]byte(`5`), response)
+	case "get_key_references":
+		if args[0] != 5 {
+			return fmt.Errorf("unexpected API ID %v", args[0])
+		}
+		encoded, err := json.Marshal(args[2])
+		if err != nil {
+			return err
+		}
+		node.params = string(encoded)
+		return json.Unmarshal([]byte(`[["alice","bob"],[]]`), response)
+	default:
+		return fmt.Errorf("unexpected method %v", args[1])
+	}
+}
+
+func TestGetKeyReferences(t *testing.T) {
+	node := &fakeNode{}
+	api, err := NewAPI(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refs, err := api.GetKeyReferences([]string{"STM6MRyAjQq8ud7hVNYcfnVPJqcVpscN5So8BhtHuGYqET5GDW5CV", "STM7jNh5ejQoqHqWcGWFJ1v4F5CzsG3EiBuz1VooCng1cH5QpJD27"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.params != `[["STM6MRyAjQq8ud7hVNYcfnVPJqcVpscN5So8BhtHuGYqET5GDW5CV","STM7jNh5ejQoqHqWcGWFJ1v4F5CzsG3EiBuz1VooCng1cH5QpJD27"]]` {
+		t.Errorf("unexpected get_key_references params %v", node.params)
+	}
+	if len(refs) != 2 || len(refs[0]) != 2 || refs[0][1] != "bob" || len(refs[1]) != 0 {
+		t.Errorf("unexpected key references: %v", refs)
+	}
+}
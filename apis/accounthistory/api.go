@@ -0,0 +1,69 @@
+package accounthistory
+
+import (
+	// Stdlib
+	"encoding/json"
+
+	// RPC
+	"github.com/asuleymanov/rpc/interfaces"
+
+	// Vendor
+	"github.com/pkg/errors"
+)
+
+const APIID = "account_history_api"
+
+type API struct {
+	caller interfaces.Caller
+}
+
+func NewAPI(caller interfaces.Caller) *API {
+	return &API{caller}
+}
+
+//Raw calls account_history_api.<method> using the appbase method style with named arguments.
+func (api *API) Raw(method string, params interface{}) (*json.RawMessage, error) {
+	var resp json.RawMessage
+	if err := api.caller.Call(APIID+"."+method, params, &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to call %v\n", APIID, method)
+	}
+	return &resp, nil
+}
+
+//get_account_history
+//Start is the sequence number of the last entry to return, -1 means the newest one.
+func (api *API) GetAccountHistory(account string, start int64, limit uint32) ([]*HistoryEntry, error) {
+	raw, err := api.Raw("get_account_history", map[string]interface{}{
+		"account": account,
+		"start":   start,
+		"limit":   limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		History []*HistoryEntry `json:"history"`
+	}
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_account_history response", APIID)
+	}
+	return resp.History, nil
+}
+
+//get_ops_in_block
+func (api *API) GetOpsInBlock(blockNum uint32, onlyVirtual bool) ([]*OperationObject, error) {
+	raw, err := api.Raw("get_ops_in_block", map[string]interface{}{
+		"block_num":    blockNum,
+		"only_virtual": onlyVirtual,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Ops []*OperationObject `json:"ops"`
+	}
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_ops_in_block response", APIID)
+	}
+	return resp.Ops, nil
+}
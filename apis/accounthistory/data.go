@@ -0,0 +1,47 @@
+package accounthistory
+
+import (
+	// Stdlib
+	"encoding/json"
+
+	// RPC
+	"github.com/asuleymanov/rpc/apis/appbase"
+	"github.com/asuleymanov/rpc/types"
+
+	// Vendor
+	"github.com/pkg/errors"
+)
+
+type OperationObject struct {
+	TrxID      string      `json:"trx_id"`
+	Block      uint32      `json:"block"`
+	TrxInBlock uint32      `json:"trx_in_block"`
+	OpInTrx    uint32      `json:"op_in_trx"`
+	VirtualOp  uint32      `json:"virtual_op"`
+	Timestamp  *types.Time `json:"timestamp"`
+	Op         *Operation  `json:"op"`
+}
+
+//HistoryEntry is a single [sequence, operation] pair of the account history.
+type HistoryEntry struct {
+	Sequence  uint64
+	Operation *OperationObject
+}
+
+//UnmarshalJSON decodes the [sequence, operation] pair.
+func (entry *HistoryEntry) UnmarshalJSON(data []byte) error {
+	var pair []json.RawMessage
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return errors.Errorf("invalid history entry: %v", string(data))
+	}
+	if err := json.Unmarshal(pair[0], &entry.Sequence); err != nil {
+		return err
+	}
+	return json.Unmarshal(pair[1], &entry.Operation)
+}
+
+//Operation is an operation in the appbase format, see appbase.Operation.
+type Operation = appbase.Operation
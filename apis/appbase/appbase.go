@@ -0,0 +1,36 @@
+//Package appbase holds the types and helpers shared by the APIs of the appbase plugins.
+package appbase
+
+import (
+	// Stdlib
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+//Operation is an operation in the appbase format, e.g. {"type":"vote_operation","value":{...}}.
+//
+//The value is kept raw, because appbase encodes assets as objects,
+//which the types package does not support.
+type Operation struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+//Name returns the operation name as used by types.OpType, e.g. "vote".
+func (op *Operation) Name() string {
+	return strings.TrimSuffix(op.Type, "_operation")
+}
+
+//BlockNumber returns the number of the block with the given ID,
+//which is stored in the first 4 bytes of the ID, or 0 when the ID is invalid.
+func BlockNumber(blockID string) uint32 {
+	if len(blockID) < 8 {
+		return 0
+	}
+	num, err := strconv.ParseUint(blockID[:8], 16, 32)
+	if err != nil {
+		return 0
+	}
+	return uint32(num)
+}
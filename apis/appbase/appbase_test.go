@@ -0,0 +1,26 @@
+package appbase
+
+import (
+	// Stdlib
+	"testing"
+)
+
+func TestOperation_Name(t *testing.T) {
+	op := &Operation{Type: "vote_operation"}
+	if name := op.Name(); name != "vote" {
+		t.Errorf("expected vote, got %v", name)
+	}
+}
+
+func TestBlockNumber(t *testing.T) {
+	for id, num := range map[string]uint32{
+		"0000000109833ce528d5bbfb3f6225b39ee10086": 1,
+		"01312d00b1d3a0d0b2d1a01ec58c6c7e4ea36ad5": 20000000,
+		"":         0,
+		"0000zzzz": 0,
+	} {
+		if got := BlockNumber(id); got != num {
+			t.Errorf("%q: expected %v, got %v", id, num, got)
+		}
+	}
+}
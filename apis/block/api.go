@@ -0,0 +1,92 @@
+package block
+
+import (
+	// Stdlib
+	"encoding/json"
+
+	// RPC
+	"github.com/asuleymanov/rpc/apis/appbase"
+	"github.com/asuleymanov/rpc/interfaces"
+
+	// Vendor
+	"github.com/pkg/errors"
+)
+
+const APIID = "block_api"
+
+type API struct {
+	caller interfaces.Caller
+}
+
+func NewAPI(caller interfaces.Caller) *API {
+	return &API{caller}
+}
+
+//Raw calls block_api.<method> using the appbase method style with named arguments.
+func (api *API) Raw(method string, params interface{}) (*json.RawMessage, error) {
+	var resp json.RawMessage
+	if err := api.caller.Call(APIID+"."+method, params, &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to call %v\n", APIID, method)
+	}
+	return &resp, nil
+}
+
+//get_block_header
+func (api *API) GetBlockHeader(blockNum uint32) (*BlockHeader, error) {
+	raw, err := api.Raw("get_block_header", map[string]interface{}{"block_num": blockNum})
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Header *BlockHeader `json:"header"`
+	}
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_block_header response", APIID)
+	}
+	if resp.Header != nil {
+		resp.Header.Number = blockNum
+	}
+	return resp.Header, nil
+}
+
+//get_block
+func (api *API) GetBlock(blockNum uint32) (*Block, error) {
+	raw, err := api.Raw("get_block", map[string]interface{}{"block_num": blockNum})
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Block *Block `json:"block"`
+	}
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_block response", APIID)
+	}
+	if resp.Block != nil {
+		resp.Block.Number = blockNum
+	}
+	return resp.Block, nil
+}
+
+//get_block_range
+func (api *API) GetBlockRange(startingBlockNum, count uint32) ([]*Block, error) {
+	raw, err := api.Raw("get_block_range", map[string]interface{}{
+		"starting_block_num": startingBlockNum,
+		"count":              count,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Blocks []*Block `json:"blocks"`
+	}
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_block_range response", APIID)
+	}
+	for i, block := range resp.Blocks {
+		block.Number = appbase.BlockNumber(block.BlockID)
+		if block.Number == 0 {
+			block.Number = startingBlockNum + uint32(i)
+		}
+	}
+	return resp.Blocks, nil
+}
@@ -0,0 +1,42 @@
+package block
+
+import (
+	// RPC
+	"github.com/asuleymanov/rpc/apis/appbase"
+	"github.com/asuleymanov/rpc/types"
+)
+
+type BlockHeader struct {
+	Number                uint32        `json:"-"`
+	Previous              string        `json:"previous"`
+	Timestamp             *types.Time   `json:"timestamp"`
+	Witness               string        `json:"witness"`
+	TransactionMerkleRoot string        `json:"transaction_merkle_root"`
+	Extensions            []interface{} `json:"extensions"`
+}
+
+type Block struct {
+	Number                uint32         `json:"-"`
+	BlockID               string         `json:"block_id"`
+	Previous              string         `json:"previous"`
+	Timestamp             *types.Time    `json:"timestamp"`
+	Witness               string         `json:"witness"`
+	TransactionMerkleRoot string         `json:"transaction_merkle_root"`
+	Extensions            []interface{}  `json:"extensions"`
+	WitnessSignature      string         `json:"witness_signature"`
+	SigningKey            string         `json:"signing_key"`
+	Transactions          []*Transaction `json:"transactions"`
+	TransactionIDs        []string       `json:"transaction_ids"`
+}
+
+type Transaction struct {
+	RefBlockNum    types.UInt16  `json:"ref_block_num"`
+	RefBlockPrefix types.UInt32  `json:"ref_block_prefix"`
+	Expiration     *types.Time   `json:"expiration"`
+	Operations     []*Operation  `json:"operations"`
+	Extensions     []interface{} `json:"extensions"`
+	Signatures     []string      `json:"signatures"`
+}
+
+//Operation is an operation in the appbase format, see appbase.Operation.
+type Operation = appbase.Operation
@@ -2,11 +2,15 @@ package database
 
 import (
 	// Stdlib
+	"context"
 	"encoding/json"
+	"sync"
+	"time"
 
 	// Vendor
 	"github.com/pkg/errors"
 
+	"github.com/asuleymanov/rpc/apis/appbase"
 	"github.com/asuleymanov/rpc/interfaces"
 	"github.com/asuleymanov/rpc/types"
 )
@@ -26,6 +30,9 @@ func NewAPI(caller interfaces.Caller) *API {
 
 var EmptyParams = []string{}
 
+//dateLayout is the time format expected by the API.
+const dateLayout = "2006-01-02T15:04:05"
+
 func (api *API) Raw(method string, params interface{}) (*json.RawMessage, error) {
 	var resp json.RawMessage
 	if err := api.caller.Call(method, params, &resp); err != nil {
@@ -38,7 +45,79 @@ func (api *API) Raw(method string, params interface{}) (*json.RawMessage, error)
 
 //set_pending_transaction_callback       | *NONE* | *NONE* |
 
-//set_block_applied_callback             | *NONE* | *NONE* |
+//set_block_applied_callback
+//SubscribeBlocks sends the header of every applied block into the returned channel
+//until ctx is cancelled. The underlying transport must implement interfaces.Subscriber.
+func (api *API) SubscribeBlocks(ctx context.Context) (<-chan *BlockHeader, error) {
+	subscriber, ok := api.caller.(interfaces.Subscriber)
+	if !ok {
+		return nil, errors.Errorf("steem-go: %v: transport does not support subscriptions", APIID)
+	}
+
+	// Notices are queued so that a slow receiver never blocks the transport.
+	var (
+		mu      sync.Mutex
+		queue   []*BlockHeader
+		last    uint32
+		pending = make(chan struct{}, 1)
+	)
+	notify := func(payload json.RawMessage) {
+		var headers []*BlockHeader
+		if err := json.Unmarshal(payload, &headers); err != nil {
+			return
+		}
+		mu.Lock()
+		for _, header := range headers {
+			header.Number = appbase.BlockNumber(header.Previous) + 1
+			// Skip duplicates, e.g. after resubscribing.
+			if header.Number <= last {
+				continue
+			}
+			last = header.Number
+			queue = append(queue, header)
+		}
+		mu.Unlock()
+
+		select {
+		case pending <- struct{}{}:
+		default:
+		}
+	}
+
+	unsubscribe, err := subscriber.Subscribe("set_block_applied_callback", func(callbackID uint32) interface{} {
+		return []interface{}{callbackID}
+	}, notify)
+	if err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to call set_block_applied_callback", APIID)
+	}
+
+	blocks := make(chan *BlockHeader)
+	go func() {
+		defer close(blocks)
+		defer unsubscribe()
+		for {
+			select {
+			case <-pending:
+			case <-ctx.Done():
+				return
+			}
+
+			mu.Lock()
+			headers := queue
+			queue = nil
+			mu.Unlock()
+
+			for _, header := range headers {
+				select {
+				case blocks <- header:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return blocks, nil
+}
 
 //cancel_all_subscriptions               | *NONE* | *NONE* |
 
@@ -56,8 +135,16 @@ func (api *API) GetTrendingTags(afterTag string, limit uint32) ([]*TrendingTags,
 }
 
 //get_tags_used_by_author
-func (api *API) GetTagsUsedByAuthor(accountName string) (*json.RawMessage, error) {
-	return api.Raw("get_tags_used_by_author", []interface{}{accountName})
+func (api *API) GetTagsUsedByAuthor(accountName string) ([]*TagsUsed, error) {
+	raw, err := api.Raw("get_tags_used_by_author", []interface{}{accountName})
+	if err != nil {
+		return nil, err
+	}
+	var resp []*TagsUsed
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_tags_used_by_author response", APIID)
+	}
+	return resp, nil
 }
 
 //get_discussions_by_trending
@@ -138,6 +225,32 @@ func (api *API) GetDiscussionsByPayout(query *DiscussionQuery) ([]*Content, erro
 	return resp, nil
 }
 
+//get_comment_discussions_by_payout
+func (api *API) GetCommentDiscussionsByPayout(query *DiscussionQuery) ([]*Content, error) {
+	raw, err := api.Raw("get_comment_discussions_by_payout", query)
+	if err != nil {
+		return nil, err
+	}
+	var resp []*Content
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_comment_discussions_by_payout response", APIID)
+	}
+	return resp, nil
+}
+
+//get_post_discussions_by_payout
+func (api *API) GetPostDiscussionsByPayout(query *DiscussionQuery) ([]*Content, error) {
+	raw, err := api.Raw("get_post_discussions_by_payout", query)
+	if err != nil {
+		return nil, err
+	}
+	var resp []*Content
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_post_discussions_by_payout response", APIID)
+	}
+	return resp, nil
+}
+
 //get_discussions_by_votes
 func (api *API) GetDiscussionsByVotes(query *DiscussionQuery) ([]*Content, error) {
 	raw, err := api.Raw("get_discussions_by_votes", query)
@@ -271,8 +384,16 @@ func (api *API) GetOpsInBlock(blockNum uint32, only_virtual bool) ([]*types.Oper
 }
 
 //get_state
-func (api *API) GetState(path string) (*json.RawMessage, error) {
-	return api.Raw("get_state", []string{path})
+func (api *API) GetState(path string) (*State, error) {
+	raw, err := api.Raw("get_state", []string{path})
+	if err != nil {
+		return nil, err
+	}
+	var resp State
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_state response", APIID)
+	}
+	return &resp, nil
 }
 
 //get_trending_categories
@@ -289,18 +410,42 @@ func (api *API) GetTrendingCategories(after string, limit uint32) ([]*Categories
 }
 
 //get_best_categories
-func (api *API) GetBestCategories(after string, limit uint32) (*json.RawMessage, error) {
-	return api.Raw("get_best_categories", []interface{}{after, limit})
+func (api *API) GetBestCategories(after string, limit uint32) ([]*Categories, error) {
+	raw, err := api.Raw("get_best_categories", []interface{}{after, limit})
+	if err != nil {
+		return nil, err
+	}
+	var resp []*Categories
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_best_categories response", APIID)
+	}
+	return resp, nil
 }
 
 //get_active_categories
-func (api *API) GetActiveCategories(after string, limit uint32) (*json.RawMessage, error) {
-	return api.Raw("get_active_categories", []interface{}{after, limit})
+func (api *API) GetActiveCategories(after string, limit uint32) ([]*Categories, error) {
+	raw, err := api.Raw("get_active_categories", []interface{}{after, limit})
+	if err != nil {
+		return nil, err
+	}
+	var resp []*Categories
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_active_categories response", APIID)
+	}
+	return resp, nil
 }
 
 //get_recent_categories
-func (api *API) GetRecentCategories(after string, limit uint32) (*json.RawMessage, error) {
-	return api.Raw("get_recent_categories", []interface{}{after, limit})
+func (api *API) GetRecentCategories(after string, limit uint32) ([]*Categories, error) {
+	raw, err := api.Raw("get_recent_categories", []interface{}{after, limit})
+	if err != nil {
+		return nil, err
+	}
+	var resp []*Categories
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_recent_categories response", APIID)
+	}
+	return resp, nil
 }
 
 //get_config
@@ -407,8 +552,32 @@ func (api *API) GetNextScheduledHardfork() (*NextScheduledHardfork, error) {
 	return &resp, nil
 }
 
+//get_reward_fund
+func (api *API) GetRewardFund(name string) (*RewardFund, error) {
+	raw, err := api.Raw("get_reward_fund", []interface{}{name})
+	if err != nil {
+		return nil, err
+	}
+	var resp RewardFund
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_reward_fund response", APIID)
+	}
+	return &resp, nil
+}
+
 //get_key_references
-//Unfortunately to say what this command does is not possible. (Any call to it leads to an error).
+//Deprecated in steemd in favour of account_by_key_api, some nodes answer with an error.
+func (api *API) GetKeyReferences(keys []string) ([][]string, error) {
+	raw, err := api.Raw("get_key_references", [][]string{keys})
+	if err != nil {
+		return nil, err
+	}
+	var resp [][]string
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_key_references response", APIID)
+	}
+	return resp, nil
+}
 
 //get_accounts
 func (api *API) GetAccounts(accountNames []string) ([]*Account, error) {
@@ -424,11 +593,23 @@ func (api *API) GetAccounts(accountNames []string) ([]*Account, error) {
 }
 
 //get_account_references
-//Unfortunately to say what this command does is not possible. (Any call to it leads to an error).
+//Not implemented by steemd, the node fails every call to it, so the call is not sent.
+//The result would be the IDs of the accounts referencing accountID.
+func (api *API) GetAccountReferences(accountID uint32) ([]uint32, error) {
+	return nil, errors.Errorf("steem-go: %v: get_account_references is not implemented by steemd", APIID)
+}
 
 //lookup_account_names
-func (api *API) LookupAccountNames(accountNames []string) (*json.RawMessage, error) {
-	return api.Raw("lookup_account_names", [][]string{accountNames})
+func (api *API) LookupAccountNames(accountNames []string) ([]*Account, error) {
+	raw, err := api.Raw("lookup_account_names", [][]string{accountNames})
+	if err != nil {
+		return nil, err
+	}
+	var resp []*Account
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal lookup_account_names response", APIID)
+	}
+	return resp, nil
 }
 
 //lookup_accounts
@@ -499,29 +680,109 @@ func (api *API) GetAccountHistory(account string, from int64, limit uint32) ([]*
 	return resp, nil
 }
 
+//GetAccountHistoryEntries is the same as GetAccountHistory,
+//only the sequence number of every operation is kept as well.
+func (api *API) GetAccountHistoryEntries(account string, from int64, limit uint32) ([]*AccountHistoryEntry, error) {
+	raw, err := api.Raw("get_account_history", []interface{}{account, from, limit})
+	if err != nil {
+		return nil, err
+	}
+	var resp []*AccountHistoryEntry
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_account_history response", APIID)
+	}
+	return resp, nil
+}
+
 //get_owner_history
-func (api *API) GetOwnerHistory(accountName string) (*json.RawMessage, error) {
-	return api.Raw("get_owner_history", []interface{}{accountName})
+func (api *API) GetOwnerHistory(accountName string) ([]*OwnerHistory, error) {
+	raw, err := api.Raw("get_owner_history", []interface{}{accountName})
+	if err != nil {
+		return nil, err
+	}
+	var resp []*OwnerHistory
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_owner_history response", APIID)
+	}
+	return resp, nil
 }
 
 //get_recovery_request
-func (api *API) GetRecoveryRequest(accountName string) (*json.RawMessage, error) {
-	return api.Raw("get_recovery_request", []interface{}{accountName})
+func (api *API) GetRecoveryRequest(accountName string) (*RecoveryRequest, error) {
+	raw, err := api.Raw("get_recovery_request", []interface{}{accountName})
+	if err != nil {
+		return nil, err
+	}
+	var resp *RecoveryRequest
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_recovery_request response", APIID)
+	}
+	return resp, nil
 }
 
 //get_escrow
-func (api *API) GetEscrow(from string, escrow_id uint32) (*json.RawMessage, error) {
-	return api.Raw("get_escrow", []interface{}{from, escrow_id})
+func (api *API) GetEscrow(from string, escrow_id uint32) (*Escrow, error) {
+	raw, err := api.Raw("get_escrow", []interface{}{from, escrow_id})
+	if err != nil {
+		return nil, err
+	}
+	var resp *Escrow
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_escrow response", APIID)
+	}
+	return resp, nil
 }
 
 //get_withdraw_routes
-func (api *API) GetWithdrawRoutes(accountName string, withdraw_route_type string) (*json.RawMessage, error) {
-	return api.Raw("get_withdraw_routes", []interface{}{accountName, withdraw_route_type})
+func (api *API) GetWithdrawRoutes(accountName string, withdraw_route_type string) ([]*WithdrawRoute, error) {
+	raw, err := api.Raw("get_withdraw_routes", []interface{}{accountName, withdraw_route_type})
+	if err != nil {
+		return nil, err
+	}
+	var resp []*WithdrawRoute
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_withdraw_routes response", APIID)
+	}
+	return resp, nil
 }
 
 //get_account_bandwidth
-func (api *API) GetAccountBandwidth(accountName string, bandwidth_type uint32) (*json.RawMessage, error) {
-	return api.Raw("get_account_bandwidth", []interface{}{accountName, bandwidth_type})
+func (api *API) GetAccountBandwidth(accountName string, bandwidth_type uint32) (*AccountBandwidth, error) {
+	raw, err := api.Raw("get_account_bandwidth", []interface{}{accountName, bandwidth_type})
+	if err != nil {
+		return nil, err
+	}
+	var resp *AccountBandwidth
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_account_bandwidth response", APIID)
+	}
+	return resp, nil
+}
+
+//get_vesting_delegations
+func (api *API) GetVestingDelegations(account, from string, limit uint32) ([]*VestingDelegation, error) {
+	raw, err := api.Raw("get_vesting_delegations", []interface{}{account, from, limit})
+	if err != nil {
+		return nil, err
+	}
+	var resp []*VestingDelegation
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_vesting_delegations response", APIID)
+	}
+	return resp, nil
+}
+
+//get_expiring_vesting_delegations
+func (api *API) GetExpiringVestingDelegations(account string, from time.Time, limit uint32) ([]*VestingDelegationExpiration, error) {
+	raw, err := api.Raw("get_expiring_vesting_delegations", []interface{}{account, &types.Time{Time: &from}, limit})
+	if err != nil {
+		return nil, err
+	}
+	var resp []*VestingDelegationExpiration
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_expiring_vesting_delegations response", APIID)
+	}
+	return resp, nil
 }
 
 //get_savings_withdraw_from
@@ -580,13 +841,29 @@ func (api *API) GetOpenOrders(accountName string) ([]*OpenOrders, error) {
 }
 
 //get_liquidity_queue
-func (api *API) GetLiquidityQueue(startAccount string, limit uint32) (*json.RawMessage, error) {
-	return api.Raw("get_liquidity_queue", []interface{}{startAccount, limit})
+func (api *API) GetLiquidityQueue(startAccount string, limit uint32) ([]*LiquidityBalance, error) {
+	raw, err := api.Raw("get_liquidity_queue", []interface{}{startAccount, limit})
+	if err != nil {
+		return nil, err
+	}
+	var resp []*LiquidityBalance
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_liquidity_queue response", APIID)
+	}
+	return resp, nil
 }
 
 //get_transaction_hex
-func (api *API) GetTransactionHex(trx *types.Transaction) (*json.RawMessage, error) {
-	return api.Raw("get_transaction_hex", []interface{}{&trx})
+func (api *API) GetTransactionHex(trx *types.Transaction) (string, error) {
+	raw, err := api.Raw("get_transaction_hex", []interface{}{&trx})
+	if err != nil {
+		return "", err
+	}
+	var resp string
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return "", errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_transaction_hex response", APIID)
+	}
+	return resp, nil
 }
 
 //get_transaction
@@ -602,7 +879,18 @@ func (api *API) GetTransaction(id string) (*types.Transaction, error) {
 	return &resp, nil
 }
 
-//get_required_signatures                | *NONE* | *NONE* |
+//get_required_signatures
+func (api *API) GetRequiredSignatures(trx *types.Transaction, availableKeys []string) ([]string, error) {
+	raw, err := api.Raw("get_required_signatures", []interface{}{&trx, availableKeys})
+	if err != nil {
+		return nil, err
+	}
+	var resp []string
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_required_signatures response", APIID)
+	}
+	return resp, nil
+}
 
 //get_potential_signatures
 func (api *API) GetPotentialSignatures(trx *types.Transaction) ([]string, error) {
@@ -697,6 +985,12 @@ func (api *API) GetDiscussionsByAuthorBeforeDate(Author, Permlink, Date string,
 	return resp, nil
 }
 
+//GetDiscussionsByAuthorBeforeDateTime is the same as GetDiscussionsByAuthorBeforeDate,
+//only the date is passed as time.Time.
+func (api *API) GetDiscussionsByAuthorBeforeDateTime(author, permlink string, date time.Time, limit uint32) ([]*Content, error) {
+	return api.GetDiscussionsByAuthorBeforeDate(author, permlink, date.UTC().Format(dateLayout), limit)
+}
+
 //get_replies_by_last_update
 func (api *API) GetRepliesByLastUpdate(startAuthor, startPermlink string, limit uint32) ([]*Content, error) {
 	raw, err := api.Raw("get_replies_by_last_update", []interface{}{startAuthor, startPermlink, limit})
@@ -738,8 +1032,8 @@ func (api *API) GetWitnessByAccount(author string) (*Witness, error) {
 
 //get_witnesses_by_vote
 func (api *API) GetWitnessByVote(author string, limit uint) ([]*Witness, error) {
-	if limit > 1000 {
-		return nil, errors.New("GetWitnessByVote: limit must not exceed 1000")
+	if limit > 100 {
+		return nil, errors.New("GetWitnessByVote: limit must not exceed 100")
 	}
 	raw, err := api.Raw("get_witnesses_by_vote", []interface{}{author, limit})
 	if err != nil {
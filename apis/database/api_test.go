@@ -0,0 +1,265 @@
+package database
+
+import (
+	// Stdlib
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	// RPC
+	"github.com/asuleymanov/rpc/types"
+)
+
+// fixtureCaller answers every call with the content of testdata/<method>.json.
+type fixtureCaller struct {
+	params string
+}
+
+func (caller *fixtureCaller) Call(method string, params, response interface{}) error {
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	caller.params = string(encoded)
+
+	data, err := ioutil.ReadFile(filepath.Join("testdata", method+".json"))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, response)
+}
+
+func TestTypedResponses(t *testing.T) {
+	api := NewAPI(&fixtureCaller{})
+
+	tags, err := api.GetTagsUsedByAuthor("steemitblog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 3 || tags[1].Tag != "steemit" || tags[1].Count != 15 {
+		t.Errorf("unexpected tags: %+v", tags)
+	}
+
+	state, err := api.GetState("/trending/steem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Props.HeadBlockNumber != 20000000 || state.FeedPrice.Base != "3.719 SBD" {
+		t.Errorf("unexpected state: %+v", state)
+	}
+	if content := state.Content["steemitblog/steemit-update-02162018"]; content == nil || content.Title != "Steemit Update 02/16/2018" {
+		t.Errorf("unexpected state content: %+v", state.Content)
+	}
+	if idx := state.DiscussionIdx["steem"]; idx == nil || len(idx.Trending) != 1 {
+		t.Errorf("unexpected discussion index: %+v", state.DiscussionIdx)
+	}
+
+	categories, err := api.GetBestCategories("", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(categories) != 0 {
+		t.Errorf("unexpected categories: %+v", categories)
+	}
+
+	accounts, err := api.LookupAccountNames([]string{"steemit", "non-existing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 2 || accounts[0].Name != "steemit" || accounts[1] != nil {
+		t.Fatalf("unexpected accounts: %+v", accounts)
+	}
+	if accounts[0].VestingShares != "90039851836.689703 VESTS" || accounts[0].LifetimeBandwidth != "4911000000" ||
+		accounts[0].AverageBandwidth.String() != "27851598420" {
+		t.Errorf("unexpected account: %+v", accounts[0])
+	}
+	expectTime(t, accounts[0].Created, "2016-03-24T17:00:21")
+
+	history, err := api.GetOwnerHistory("steemit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].PreviousOwnerAuthority.WeightThreshold != 1 {
+		t.Errorf("unexpected owner history: %+v", history)
+	}
+	expectTime(t, history[0].LastValidTime, "2016-07-14T21:33:33")
+
+	request, err := api.GetRecoveryRequest("steemit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request.AccountToRecover != "steemit" || len(request.NewOwnerAuthority.KeyAuths) != 1 {
+		t.Errorf("unexpected recovery request: %+v", request)
+	}
+
+	escrow, err := api.GetEscrow("someguy123", 72526562)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if escrow.EscrowID != 72526562 || escrow.Agent != "steemit" || !escrow.ToApproved || escrow.AgentApproved {
+		t.Errorf("unexpected escrow: %+v", escrow)
+	}
+	expectTime(t, escrow.EscrowExpiration, "2018-02-26T10:00:00")
+
+	routes, err := api.GetWithdrawRoutes("steemit", WithdrawRouteOutgoing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 1 || routes[0].Percent != 2500 || !routes[0].AutoVest {
+		t.Errorf("unexpected withdraw routes: %+v", routes)
+	}
+
+	bandwidth, err := api.GetAccountBandwidth("steemit", BandwidthForum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bandwidth.Type != "forum" || bandwidth.AverageBandwidth.String() != "1731926296500" {
+		t.Errorf("unexpected bandwidth: %+v", bandwidth)
+	}
+
+	queue, err := api.GetLiquidityQueue("", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queue) != 1 || queue[0].Weight.String() != "2365467853000" {
+		t.Errorf("unexpected liquidity queue: %+v", queue)
+	}
+
+	hex, err := api.GetTransactionHex(&types.Transaction{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex != "00000000000000000000000000" {
+		t.Errorf("unexpected transaction hex: %v", hex)
+	}
+}
+
+func TestDelegationsAndRewards(t *testing.T) {
+	caller := &fixtureCaller{}
+	api := NewAPI(caller)
+
+	delegations, err := api.GetVestingDelegations("steem", "", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(delegations) != 1 || delegations[0].Delegatee != "dsound" || delegations[0].VestingShares != "29705.940162 VESTS" {
+		t.Errorf("unexpected delegations: %+v", delegations)
+	}
+	expectTime(t, delegations[0].MinDelegationTime, "2018-02-10T08:31:06")
+
+	from := time.Date(2018, 2, 17, 0, 0, 0, 0, time.UTC)
+	expiring, err := api.GetExpiringVestingDelegations("steem", from, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `["steem","2018-02-17T00:00:00",100]`; caller.params != expected {
+		t.Errorf("expected params %v, got %v", expected, caller.params)
+	}
+	if len(expiring) != 1 || expiring[0].VestingShares != "29705.940162 VESTS" {
+		t.Errorf("unexpected expiring delegations: %+v", expiring)
+	}
+	expectTime(t, expiring[0].Expiration, "2018-02-24T11:02:39")
+
+	fund, err := api.GetRewardFund("post")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fund.RecentClaims.String() != "446371802932810787" || fund.PercentCurationRewards != 2500 ||
+		fund.AuthorRewardCurve != "linear" {
+		t.Errorf("unexpected reward fund: %+v", fund)
+	}
+
+	signatures, err := api.GetRequiredSignatures(&types.Transaction{}, []string{"STM6Ezkzey8FWoEnnHHP4rxbrysJqoMmzwR2EdjD5k4wgJ5dD1stA"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signatures) != 1 {
+		t.Errorf("unexpected required signatures: %v", signatures)
+	}
+
+	references, err := api.GetKeyReferences([]string{"STM6Ezkzey8FWoEnnHHP4rxbrysJqoMmzwR2EdjD5k4wgJ5dD1stA"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(references) != 1 || len(references[0]) != 1 || references[0][0] != "steemit" {
+		t.Errorf("unexpected key references: %v", references)
+	}
+}
+
+func TestDiscussions(t *testing.T) {
+	caller := &fixtureCaller{}
+	api := NewAPI(caller)
+	query := &DiscussionQuery{Tag: "", Limit: 2}
+
+	posts, err := api.GetDiscussionsByPayout(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"tag":"","limit":2,"filter_tags":null,"parent_permlink":""}`; caller.params != expected {
+		t.Errorf("expected params %v, got %v", expected, caller.params)
+	}
+	if len(posts) != 2 || posts[0].Author != "utopian-io" || posts[0].PendingPayoutValue != "1487.306 SBD" ||
+		posts[0].NetRshares.String() != "268153431752146" || len(posts[0].ActiveVotes) != 2 {
+		t.Fatalf("unexpected discussions by payout: %+v", posts)
+	}
+	if vote := posts[0].ActiveVotes[1]; vote.Voter != "gtg" || vote.Rshares.String() != "2129338127" || vote.Percent != 100 {
+		t.Errorf("unexpected vote: %+v", vote)
+	}
+	if tags := posts[1].JsonMetadata.Tags; len(tags) != 3 || tags[0] != "steemit" {
+		t.Errorf("unexpected tags: %v", tags)
+	}
+	expectTime(t, posts[1].CashoutTime, "2018-02-23T23:18:06")
+
+	posts, err = api.GetPostDiscussionsByPayout(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != 1 || !posts[0].IsStory() || posts[0].Permlink != "steemit-update-02162018" {
+		t.Errorf("unexpected post discussions by payout: %+v", posts)
+	}
+
+	comments, err := api.GetCommentDiscussionsByPayout(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 1 || comments[0].IsStory() || comments[0].ParentAuthor != "steemitblog" ||
+		comments[0].Depth.String() != "1" {
+		t.Errorf("unexpected comment discussions by payout: %+v", comments)
+	}
+
+	date := time.Date(2016, 4, 1, 2, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	posts, err = api.GetDiscussionsByAuthorBeforeDateTime("steemit", "", date, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `["steemit","","2016-04-01T00:00:00",1]`; caller.params != expected {
+		t.Errorf("expected params %v, got %v", expected, caller.params)
+	}
+	if len(posts) != 1 || posts[0].Permlink != "firstpost" || posts[0].TotalPayoutValue != "0.942 SBD" ||
+		posts[0].AuthorReputation.String() != "64363178807" {
+		t.Errorf("unexpected discussions by author before date: %+v", posts)
+	}
+	expectTime(t, posts[0].Created, "2016-03-30T18:30:18")
+}
+
+func TestAccountReferences(t *testing.T) {
+	caller := &fixtureCaller{}
+	if _, err := NewAPI(caller).GetAccountReferences(28); err == nil {
+		t.Error("expected an error")
+	}
+	if caller.params != "" {
+		t.Errorf("expected no call, got params %v", caller.params)
+	}
+}
+
+func expectTime(t *testing.T, got *types.Time, expected string) {
+	if got == nil || got.Time == nil {
+		t.Errorf("expected %v, got nil", expected)
+		return
+	}
+	if s := got.Format("2006-01-02T15:04:05"); s != expected {
+		t.Errorf("expected %v, got %v", expected, s)
+	}
+}
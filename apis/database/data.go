@@ -4,7 +4,9 @@ import (
 	// Stdlib
 	"encoding/json"
 	"strconv"
-	"strings"
+
+	// Vendor
+	"github.com/pkg/errors"
 
 	// RPC
 	"github.com/asuleymanov/rpc/types"
@@ -157,6 +159,7 @@ type DynamicGlobalProperties struct {
 	TotalActivityFundShares  *types.Int  `json:"total_activity_fund_shares"`
 	SBDInterestRate          *types.Int  `json:"sbd_interest_rate"`
 	MaxVirtualBandwidth      *types.Int  `json:"max_virtual_bandwidth"`
+	VotePowerReserveRate     *types.Int  `json:"vote_power_reserve_rate"`
 }
 
 type Block struct {
@@ -171,51 +174,52 @@ type Block struct {
 }
 
 type Content struct {
-	ID                      *types.ID        `json:"id"`
-	Author                  string           `json:"author"`
-	Permlink                string           `json:"permlink"`
-	Category                string           `json:"category"`
-	ParentAuthor            string           `json:"parent_author"`
-	ParentPermlink          string           `json:"parent_permlink"`
-	Title                   string           `json:"title"`
-	Body                    string           `json:"body"`
-	JsonMetadata            *ContentMetadata `json:"json_metadata"`
-	LastUpdate              *types.Time      `json:"last_update"`
-	Created                 *types.Time      `json:"created"`
-	Active                  *types.Time      `json:"active"`
-	LastPayout              *types.Time      `json:"last_payout"`
-	Depth                   *types.Int       `json:"depth"`
-	Children                *types.Int       `json:"children"`
-	ChildrenRshares2        *types.Int       `json:"children_rshares2"`
-	NetRshares              *types.Int       `json:"net_rshares"`
-	AbsRshares              *types.Int       `json:"abs_rshares"`
-	VoteRshares             *types.Int       `json:"vote_rshares"`
-	ChildrenAbsRshares      *types.Int       `json:"children_abs_rshares"`
-	CashoutTime             *types.Time      `json:"cashout_time"`
-	MaxCashoutTime          *types.Time      `json:"max_cashout_time"`
-	TotalVoteWeight         *types.Int       `json:"total_vote_weight"`
-	RewardWeight            *types.Int       `json:"reward_weight"`
-	TotalPayoutValue        string           `json:"total_payout_value"`
-	CuratorPayoutValue      string           `json:"curator_payout_value"`
-	AuthorRewards           *types.Int       `json:"author_rewards"`
-	NetVotes                *types.Int       `json:"net_votes"`
-	RootComment             *types.Int       `json:"root_comment"`
-	Mode                    string           `json:"mode"`
-	MaxAcceptedPayout       string           `json:"max_accepted_payout"`
-	PercentSteemDollars     *types.Int       `json:"percent_steem_dollars"`
-	AllowReplies            bool             `json:"allow_replies"`
-	AllowVotes              bool             `json:"allow_votes"`
-	AllowCurationRewards    bool             `json:"allow_curation_rewards"`
-	URL                     string           `json:"url"`
-	RootTitle               string           `json:"root_title"`
-	PendingPayoutValue      string           `json:"pending_payout_value"`
-	TotalPendingPayoutValue string           `json:"total_pending_payout_value"`
-	ActiveVotes             []*VoteState     `json:"active_votes"`
-	Replies                 []*Content       `json:"replies"`
-	AuthorReputation        *types.Int       `json:"author_reputation"`
-	Promoted                string           `json:"promoted"`
-	BodyLength              *types.Int       `json:"body_length"`
-	RebloggedBy             []interface{}    `json:"reblogged_by"`
+	ID                      *types.ID             `json:"id"`
+	Author                  string                `json:"author"`
+	Permlink                string                `json:"permlink"`
+	Category                string                `json:"category"`
+	ParentAuthor            string                `json:"parent_author"`
+	ParentPermlink          string                `json:"parent_permlink"`
+	Title                   string                `json:"title"`
+	Body                    string                `json:"body"`
+	JsonMetadata            *ContentMetadata      `json:"json_metadata"`
+	LastUpdate              *types.Time           `json:"last_update"`
+	Created                 *types.Time           `json:"created"`
+	Active                  *types.Time           `json:"active"`
+	LastPayout              *types.Time           `json:"last_payout"`
+	Depth                   *types.Int            `json:"depth"`
+	Children                *types.Int            `json:"children"`
+	ChildrenRshares2        *types.Int            `json:"children_rshares2"`
+	NetRshares              *types.Int            `json:"net_rshares"`
+	AbsRshares              *types.Int            `json:"abs_rshares"`
+	VoteRshares             *types.Int            `json:"vote_rshares"`
+	ChildrenAbsRshares      *types.Int            `json:"children_abs_rshares"`
+	CashoutTime             *types.Time           `json:"cashout_time"`
+	MaxCashoutTime          *types.Time           `json:"max_cashout_time"`
+	TotalVoteWeight         *types.Int            `json:"total_vote_weight"`
+	RewardWeight            *types.Int            `json:"reward_weight"`
+	TotalPayoutValue        string                `json:"total_payout_value"`
+	CuratorPayoutValue      string                `json:"curator_payout_value"`
+	AuthorRewards           *types.Int            `json:"author_rewards"`
+	NetVotes                *types.Int            `json:"net_votes"`
+	RootComment             *types.Int            `json:"root_comment"`
+	Mode                    string                `json:"mode"`
+	MaxAcceptedPayout       string                `json:"max_accepted_payout"`
+	PercentSteemDollars     *types.Int            `json:"percent_steem_dollars"`
+	AllowReplies            bool                  `json:"allow_replies"`
+	AllowVotes              bool                  `json:"allow_votes"`
+	AllowCurationRewards    bool                  `json:"allow_curation_rewards"`
+	URL                     string                `json:"url"`
+	RootTitle               string                `json:"root_title"`
+	PendingPayoutValue      string                `json:"pending_payout_value"`
+	TotalPendingPayoutValue string                `json:"total_pending_payout_value"`
+	ActiveVotes             []*VoteState          `json:"active_votes"`
+	Replies                 []*Content            `json:"replies"`
+	AuthorReputation        *types.Int            `json:"author_reputation"`
+	Promoted                string                `json:"promoted"`
+	BodyLength              *types.Int            `json:"body_length"`
+	RebloggedBy             []interface{}         `json:"reblogged_by"`
+	Beneficiaries           []*types.Beneficiarie `json:"beneficiaries"`
 }
 
 func (content *Content) IsStory() bool {
@@ -228,6 +232,8 @@ type ContentMetadata struct {
 	Users []string
 	Tags  []string
 	Image []string
+	//Post is the whole metadata, nil for flag and string values.
+	Post *types.PostMetadata
 }
 
 type ContentMetadataRaw struct {
@@ -260,14 +266,15 @@ func (metadata *ContentMetadata) UnmarshalJSON(data []byte) error {
 		return nil
 	}
 
-	var raw ContentMetadataRaw
-	if err := json.NewDecoder(strings.NewReader(unquoted)).Decode(&raw); err != nil {
+	post, err := types.ParsePostMetadata(unquoted)
+	if err != nil {
 		return err
 	}
 
-	metadata.Users = raw.Users
-	metadata.Tags = raw.Tags
-	metadata.Image = raw.Image
+	metadata.Users = post.Users
+	metadata.Tags = post.Tags
+	metadata.Image = post.Image
+	metadata.Post = post
 
 	return nil
 }
@@ -351,11 +358,8 @@ type OpenOrders struct {
 	Rewarded   bool        `json:"rewarded"`
 }
 
-type AccountKeys struct {
-	WeightThreshold *types.Int    `json:"weight_threshold"`
-	AccountAuths    []interface{} `json:"account_auths"`
-	KeyAuths        []interface{} `json:"key_auths"`
-}
+// AccountKeys is the owner, active or posting authority of an account.
+type AccountKeys = types.Authority
 
 type Account struct {
 	ID                            *types.Int    `json:"id"`
@@ -395,6 +399,8 @@ type Account struct {
 	SavingsSbdLastInterestPayment *types.Time   `json:"savings_sbd_last_interest_payment"`
 	SavingsWithdrawRequests       *types.Int    `json:"savings_withdraw_requests"`
 	VestingShares                 string        `json:"vesting_shares"`
+	DelegatedVestingShares        string        `json:"delegated_vesting_shares"`
+	ReceivedVestingShares         string        `json:"received_vesting_shares"`
 	VestingWithdrawRate           string        `json:"vesting_withdraw_rate"`
 	NextVestingWithdrawal         *types.Time   `json:"next_vesting_withdrawal"`
 	Withdrawn                     *types.Int    `json:"withdrawn"`
@@ -497,3 +503,174 @@ type Categories struct {
 	Discussions  *types.Int `json:"discussions"`
 	LastUpdate   string     `json:"last_update"`
 }
+
+type TagsUsed struct {
+	Tag   string
+	Count uint32
+}
+
+// UnmarshalJSON decodes the [tag, count] pair returned by get_tags_used_by_author.
+func (tag *TagsUsed) UnmarshalJSON(data []byte) error {
+	var pair []json.RawMessage
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return errors.Errorf("invalid tag usage: %v", string(data))
+	}
+	if err := json.Unmarshal(pair[0], &tag.Tag); err != nil {
+		return err
+	}
+	return json.Unmarshal(pair[1], &tag.Count)
+}
+
+type State struct {
+	CurrentRoute    string                     `json:"current_route"`
+	Props           *DynamicGlobalProperties   `json:"props"`
+	TagIdx          *TagIndex                  `json:"tag_idx"`
+	Tags            map[string]*TrendingTags   `json:"tags"`
+	Content         map[string]*Content        `json:"content"`
+	Accounts        map[string]*Account        `json:"accounts"`
+	PowQueue        []string                   `json:"pow_queue"`
+	Witnesses       map[string]*Witness        `json:"witnesses"`
+	DiscussionIdx   map[string]*DiscussionIdx  `json:"discussion_idx"`
+	WitnessSchedule *WitnessSchedule           `json:"witness_schedule"`
+	FeedPrice       *CurrentMedianHistoryPrice `json:"feed_price"`
+	Error           string                     `json:"error"`
+}
+
+type TagIndex struct {
+	Trending []string `json:"trending"`
+}
+
+type DiscussionIdx struct {
+	Category       string   `json:"category"`
+	Trending       []string `json:"trending"`
+	Payout         []string `json:"payout"`
+	PayoutComments []string `json:"payout_comments"`
+	Trending30     []string `json:"trending30"`
+	Updated        []string `json:"updated"`
+	Created        []string `json:"created"`
+	Responses      []string `json:"responses"`
+	Active         []string `json:"active"`
+	Votes          []string `json:"votes"`
+	Maturing       []string `json:"maturing"`
+	Best           []string `json:"best"`
+	Hot            []string `json:"hot"`
+	Promoted       []string `json:"promoted"`
+	Cashout        []string `json:"cashout"`
+}
+
+type OwnerHistory struct {
+	ID                     *types.Int   `json:"id"`
+	Account                string       `json:"account"`
+	PreviousOwnerAuthority *AccountKeys `json:"previous_owner_authority"`
+	LastValidTime          *types.Time  `json:"last_valid_time"`
+}
+
+type RecoveryRequest struct {
+	ID                *types.Int   `json:"id"`
+	AccountToRecover  string       `json:"account_to_recover"`
+	NewOwnerAuthority *AccountKeys `json:"new_owner_authority"`
+	Expires           *types.Time  `json:"expires"`
+}
+
+type Escrow struct {
+	ID                   *types.Int  `json:"id"`
+	EscrowID             uint32      `json:"escrow_id"`
+	From                 string      `json:"from"`
+	To                   string      `json:"to"`
+	Agent                string      `json:"agent"`
+	RatificationDeadline *types.Time `json:"ratification_deadline"`
+	EscrowExpiration     *types.Time `json:"escrow_expiration"`
+	SbdBalance           string      `json:"sbd_balance"`
+	SteemBalance         string      `json:"steem_balance"`
+	PendingFee           string      `json:"pending_fee"`
+	ToApproved           bool        `json:"to_approved"`
+	AgentApproved        bool        `json:"agent_approved"`
+	Disputed             bool        `json:"disputed"`
+}
+
+// Withdraw route types accepted by get_withdraw_routes.
+const (
+	WithdrawRouteIncoming = "incoming"
+	WithdrawRouteOutgoing = "outgoing"
+	WithdrawRouteAll      = "all"
+)
+
+type WithdrawRoute struct {
+	FromAccount string `json:"from_account"`
+	ToAccount   string `json:"to_account"`
+	Percent     uint16 `json:"percent"`
+	AutoVest    bool   `json:"auto_vest"`
+}
+
+// Bandwidth types accepted by get_account_bandwidth.
+const (
+	BandwidthPost uint32 = iota
+	BandwidthForum
+	BandwidthMarket
+)
+
+type AccountBandwidth struct {
+	ID                  *types.Int  `json:"id"`
+	Account             string      `json:"account"`
+	Type                string      `json:"type"`
+	AverageBandwidth    *types.Int  `json:"average_bandwidth"`
+	LifetimeBandwidth   *types.Int  `json:"lifetime_bandwidth"`
+	LastBandwidthUpdate *types.Time `json:"last_bandwidth_update"`
+}
+
+type LiquidityBalance struct {
+	Account string     `json:"account"`
+	Weight  *types.Int `json:"weight"`
+}
+
+type VestingDelegation struct {
+	ID                *types.Int  `json:"id"`
+	Delegator         string      `json:"delegator"`
+	Delegatee         string      `json:"delegatee"`
+	VestingShares     string      `json:"vesting_shares"`
+	MinDelegationTime *types.Time `json:"min_delegation_time"`
+}
+
+type VestingDelegationExpiration struct {
+	ID            *types.Int  `json:"id"`
+	Delegator     string      `json:"delegator"`
+	VestingShares string      `json:"vesting_shares"`
+	Expiration    *types.Time `json:"expiration"`
+}
+
+type RewardFund struct {
+	ID                     *types.Int  `json:"id"`
+	Name                   string      `json:"name"`
+	RewardBalance          string      `json:"reward_balance"`
+	RecentClaims           *types.Int  `json:"recent_claims"`
+	LastUpdate             *types.Time `json:"last_update"`
+	ContentConstant        *types.Int  `json:"content_constant"`
+	PercentCurationRewards uint16      `json:"percent_curation_rewards"`
+	PercentContentRewards  uint16      `json:"percent_content_rewards"`
+	AuthorRewardCurve      string      `json:"author_reward_curve"`
+	CurationRewardCurve    string      `json:"curation_reward_curve"`
+}
+
+// AccountHistoryEntry is a single [sequence, operation] pair of the account history.
+type AccountHistoryEntry struct {
+	Sequence  uint64
+	Operation *types.OperationObject
+}
+
+// UnmarshalJSON decodes the [sequence, operation] pair.
+func (entry *AccountHistoryEntry) UnmarshalJSON(data []byte) error {
+	var pair []json.RawMessage
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return errors.Errorf("invalid history entry: %v", string(data))
+	}
+	if err := json.Unmarshal(pair[0], &entry.Sequence); err != nil {
+		return err
+	}
+	return json.Unmarshal(pair[1], &entry.Operation)
+}
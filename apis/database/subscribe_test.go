@@ -0,0 +1,83 @@
+package database
+
+import (
+	// Stdlib
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeNotifier is a subscriber delivering the notices passed to send.
+type fakeNotifier struct {
+	fixtureCaller
+
+	mu           sync.Mutex
+	method       string
+	notify       func(payload json.RawMessage)
+	unsubscribed chan struct{}
+}
+
+func (n *fakeNotifier) Subscribe(method string, params func(callbackID uint32) interface{}, notify func(payload json.RawMessage)) (func(), error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.method = method
+	n.notify = notify
+	return func() { close(n.unsubscribed) }, nil
+}
+
+func (n *fakeNotifier) send(payload string) {
+	n.mu.Lock()
+	notify := n.notify
+	n.mu.Unlock()
+	notify(json.RawMessage(payload))
+}
+
+func TestSubscribeBlocks(t *testing.T) {
+	notifier := &fakeNotifier{unsubscribed: make(chan struct{})}
+	api := NewAPI(notifier)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	blocks, err := api.SubscribeBlocks(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notifier.method != "set_block_applied_callback" {
+		t.Errorf("unexpected subscription method %v", notifier.method)
+	}
+
+	// The second notice repeats block 2, e.g. after resubscribing.
+	notifier.send(`[{"previous":"00000001aa","witness":"alice"},{"previous":"00000002bb","witness":"bob"}]`)
+	notifier.send(`[{"previous":"00000002bb","witness":"bob"},{"previous":"00000003cc","witness":"carol"}]`)
+	for _, expected := range []uint32{2, 3, 4} {
+		select {
+		case header := <-blocks:
+			if header.Number != expected {
+				t.Errorf("expected block %v, got %v", expected, header.Number)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("block %v not received", expected)
+		}
+	}
+
+	// Cancelling the context closes the channel and cancels the subscription.
+	cancel()
+	select {
+	case _, ok := <-blocks:
+		if ok {
+			t.Error("unexpected block after cancel")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("channel not closed after cancel")
+	}
+	select {
+	case <-notifier.unsubscribed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription not cancelled")
+	}
+
+	if _, err := NewAPI(&fixtureCaller{}).SubscribeBlocks(context.Background()); err == nil {
+		t.Error("expected an error for a transport without subscriptions")
+	}
+}
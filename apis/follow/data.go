@@ -1,8 +1,11 @@
 package follow
 
 import (
+	"bytes"
 	"encoding/json"
 	"github.com/asuleymanov/rpc/types"
+	"github.com/pkg/errors"
+	"math"
 	"strconv"
 	"strings"
 )
@@ -144,6 +147,45 @@ type AccountReputation struct {
 	Reputation interface{} `json:"reputation"`
 }
 
+// UnmarshalJSON keeps a numeric reputation as json.Number,
+// so that Raw returns it exactly even above 2^53.
+func (rep *AccountReputation) UnmarshalJSON(data []byte) error {
+	type accountReputation AccountReputation
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode((*accountReputation)(rep))
+}
+
+// Raw returns the raw reputation, nodes send it either as a number or as a string.
+func (rep *AccountReputation) Raw() (int64, error) {
+	switch value := rep.Reputation.(type) {
+	case nil:
+		return 0, nil
+	case json.Number:
+		return parseReputation(string(value))
+	case string:
+		return parseReputation(value)
+	case float64:
+		// Only set by hand, the decoded values are json.Number.
+		if value != math.Trunc(value) || math.Abs(value) > 1<<53 {
+			return 0, errors.Errorf("reputation %v of %v is not an exact integer", value, rep.Account)
+		}
+		return int64(value), nil
+	case int64:
+		return value, nil
+	default:
+		return 0, errors.Errorf("invalid reputation %v of %v", value, rep.Account)
+	}
+}
+
+func parseReputation(value string) (int64, error) {
+	raw, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid reputation %v", value)
+	}
+	return raw, nil
+}
+
 type BlogAuthors struct {
 	BlogAuthor []*BlogAuthor
 }
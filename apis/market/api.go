@@ -17,21 +17,18 @@ const APIID = "market_history_api"
 var EmptyParams = []string{}
 
 type API struct {
-	id     int
-	caller interfaces.Caller
+	caller *rpc.APICaller
 }
 
+//NewAPI creates a new API instance. The numeric API ID is resolved lazily
+//on the first call, so no error is returned when the node does not expose the API.
 func NewAPI(caller interfaces.Caller) (*API, error) {
-	id, err := rpc.GetNumericAPIID(caller, APIID)
-	if err != nil {
-		return nil, err
-	}
-	return &API{id, caller}, nil
+	return &API{rpc.NewAPICaller(caller, APIID)}, nil
 }
 
 func (api *API) Raw(method string, params interface{}) (*json.RawMessage, error) {
 	var resp json.RawMessage
-	if err := api.caller.Call("call", []interface{}{api.id, method, params}, &resp); err != nil {
+	if err := api.caller.Call(method, params, &resp); err != nil {
 		return nil, errors.Wrapf(err, "steem-go: %v: failed to call %v\n", APIID, method)
 	}
 	return &resp, nil
@@ -16,20 +16,17 @@ import (
 const APIID = "network_broadcast_api"
 
 type API struct {
-	id     int
-	caller interfaces.Caller
+	caller *rpc.APICaller
 }
 
+//NewAPI creates a new API instance. The numeric API ID is resolved lazily
+//on the first call, so no error is returned when the node does not expose the API.
 func NewAPI(caller interfaces.Caller) (*API, error) {
-	id, err := rpc.GetNumericAPIID(caller, APIID)
-	if err != nil {
-		return nil, err
-	}
-	return &API{id, caller}, nil
+	return &API{rpc.NewAPICaller(caller, APIID)}, nil
 }
 
 func (api *API) call(method string, params, resp interface{}) error {
-	return api.caller.Call("call", []interface{}{api.id, method, params}, resp)
+	return api.caller.Call(method, params, resp)
 }
 
 /*
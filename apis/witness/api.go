@@ -0,0 +1,61 @@
+package witness
+
+import (
+	// Stdlib
+	"encoding/json"
+
+	// RPC
+	"github.com/asuleymanov/rpc/interfaces"
+	"github.com/asuleymanov/rpc/internal/rpc"
+
+	// Vendor
+	"github.com/pkg/errors"
+)
+
+const APIID = "witness_api"
+
+var EmptyParams = []string{}
+
+type API struct {
+	caller *rpc.APICaller
+}
+
+//NewAPI creates a new API instance. The numeric API ID is resolved lazily
+//on the first call, so no error is returned when the node does not expose the API.
+func NewAPI(caller interfaces.Caller) (*API, error) {
+	return &API{rpc.NewAPICaller(caller, APIID)}, nil
+}
+
+func (api *API) Raw(method string, params interface{}) (*json.RawMessage, error) {
+	var resp json.RawMessage
+	if err := api.caller.Call(method, params, &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to call %v\n", APIID, method)
+	}
+	return &resp, nil
+}
+
+//get_account_bandwidth
+func (api *API) GetAccountBandwidth(accountName string, bandwidthType uint32) (*AccountBandwidth, error) {
+	raw, err := api.Raw("get_account_bandwidth", []interface{}{accountName, bandwidthType})
+	if err != nil {
+		return nil, err
+	}
+	var resp *AccountBandwidth
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_account_bandwidth response", APIID)
+	}
+	return resp, nil
+}
+
+//get_reserve_ratio
+func (api *API) GetReserveRatio() (*ReserveRatio, error) {
+	raw, err := api.Raw("get_reserve_ratio", EmptyParams)
+	if err != nil {
+		return nil, err
+	}
+	var resp ReserveRatio
+	if err := json.Unmarshal([]byte(*raw), &resp); err != nil {
+		return nil, errors.Wrapf(err, "steem-go: %v: failed to unmarshal get_reserve_ratio response", APIID)
+	}
+	return &resp, nil
+}
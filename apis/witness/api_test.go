@@ -0,0 +1,73 @@
+package witness
+
+import (
+	// Stdlib
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+// fakeNode exposes witness_api with ID 4 and records the last call.
+type fakeNode struct {
+	method string
+	params string
+}
+
+func (node *fakeNode) Call(method string, params, response interface{}) error {
+	args := params.([]interface{})
+	if method != "call" {
+		return fmt.Errorf("unexpected method %v", method)
+	}
+	var result string
+	switch args[1] {
+	case "get_api_by_name":
+		result = `4`
+	case "get_account_bandwidth":
+		result = `{"id":12,"account":"alice","type":"forum","average_bandwidth":"2156084000","lifetime_bandwidth":"52917000000","last_bandwidth_update":"2018-01-10T12:00:03"}`
+	case "get_reserve_ratio":
+		result = `{"id":0,"average_block_size":9537,"current_reserve_ratio":200000000,"max_virtual_bandwidth":"264241152000000000000"}`
+	default:
+		return fmt.Errorf("unexpected method %v", args[1])
+	}
+	if args[1] != "get_api_by_name" {
+		if args[0] != 4 {
+			return fmt.Errorf("unexpected API ID %v", args[0])
+		}
+		encoded, err := json.Marshal(args[2])
+		if err != nil {
+			return err
+		}
+		node.method, node.params = args[1].(string), string(encoded)
+	}
+	return json.Unmarshal([]byte(result), response)
+}
+
+func TestAPI(t *testing.T) {
+	node := &fakeNode{}
+	api, err := NewAPI(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bandwidth, err := api.GetAccountBandwidth("alice", BandwidthForum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.params != `["alice",1]` {
+		t.Errorf("unexpected get_account_bandwidth params %v", node.params)
+	}
+	if bandwidth.Account != "alice" || bandwidth.AverageBandwidth.Int64() != 2156084000 {
+		t.Errorf("unexpected bandwidth: %+v", bandwidth)
+	}
+
+	ratio, err := api.GetReserveRatio()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.method != "get_reserve_ratio" || node.params != `[]` {
+		t.Errorf("unexpected call %v %v", node.method, node.params)
+	}
+	if ratio.MaxVirtualBandwidth.String() != "264241152000000000000" || ratio.CurrentReserveRatio.Int64() != 200000000 {
+		t.Errorf("unexpected reserve ratio: %+v", ratio)
+	}
+}
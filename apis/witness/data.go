@@ -0,0 +1,24 @@
+package witness
+
+import (
+	// RPC
+	"github.com/asuleymanov/rpc/apis/database"
+	"github.com/asuleymanov/rpc/types"
+)
+
+//Bandwidth types accepted by get_account_bandwidth, the same as in the database API.
+const (
+	BandwidthPost   = database.BandwidthPost
+	BandwidthForum  = database.BandwidthForum
+	BandwidthMarket = database.BandwidthMarket
+)
+
+//AccountBandwidth is the bandwidth object shared with the database API.
+type AccountBandwidth = database.AccountBandwidth
+
+type ReserveRatio struct {
+	ID                  *types.Int `json:"id"`
+	AverageBlockSize    *types.Int `json:"average_block_size"`
+	CurrentReserveRatio *types.Int `json:"current_reserve_ratio"`
+	MaxVirtualBandwidth *types.Int `json:"max_virtual_bandwidth"`
+}
@@ -0,0 +1,284 @@
+package cache
+
+import (
+	// Stdlib
+	"encoding/json"
+	"sync"
+	"time"
+
+	// RPC
+	"github.com/asuleymanov/rpc/interfaces"
+	"github.com/asuleymanov/rpc/metrics"
+	"github.com/asuleymanov/rpc/middleware"
+)
+
+// DefaultMaxEntries is the number of entries after which expired entries are pruned.
+const DefaultMaxEntries = 10000
+
+// Rule specifies how long responses of a method are kept in the cache.
+type Rule struct {
+	// TTL is the maximum age of a cached response, zero means forever.
+	TTL time.Duration
+
+	// Block marks responses that are only valid for the current head block.
+	// Such entries are dropped as soon as a new head block is observed.
+	Block bool
+}
+
+// DefaultRules are the rules used unless overridden using SetRule.
+// Methods not listed here are never cached.
+var DefaultRules = map[string]Rule{
+	"get_config":                       {},
+	"get_dynamic_global_properties":    {TTL: 3 * time.Second, Block: true},
+	"get_current_median_history_price": {TTL: 3 * time.Second, Block: true},
+	"get_chain_properties":             {TTL: time.Minute},
+	"get_accounts":                     {TTL: 5 * time.Second},
+}
+
+// Stats contains cache statistics.
+type Stats struct {
+	// Hits is the number of calls served from the cache.
+	Hits uint64
+	// Misses is the number of calls forwarded to the next Caller.
+	Misses uint64
+	// Shared is the number of calls that waited for an identical call in progress.
+	Shared uint64
+}
+
+// Cache is a response cache for slow-changing chain data.
+//
+// Use Middleware to put it in front of a Caller. Concurrent identical calls
+// of cacheable methods are de-duplicated, only one of them hits the node.
+type Cache struct {
+	rules      map[string]Rule
+	maxEntries int
+	requests   *metrics.CounterVec
+
+	mu        sync.Mutex
+	entries   map[string]*entry
+	flights   map[string]*flight
+	headBlock uint32
+	stats     Stats
+}
+
+type entry struct {
+	data    json.RawMessage
+	expires time.Time
+	block   uint32
+	rule    Rule
+}
+
+type flight struct {
+	wg   sync.WaitGroup
+	data json.RawMessage
+	err  error
+}
+
+// Option represents an option that can be passed into the cache constructor.
+type Option func(*Cache)
+
+// SetRule sets the caching rule for the given method, overriding DefaultRules.
+func SetRule(method string, rule Rule) Option {
+	return func(c *Cache) {
+		c.rules[method] = rule
+	}
+}
+
+// DisableMethod makes sure the given method is never cached.
+func DisableMethod(method string) Option {
+	return func(c *Cache) {
+		delete(c.rules, method)
+	}
+}
+
+// SetMaxEntries sets the number of entries after which expired entries are pruned.
+func SetMaxEntries(n int) Option {
+	return func(c *Cache) {
+		c.maxEntries = n
+	}
+}
+
+// SetMetrics registers the steem_cache_requests_total counter into reg.
+func SetMetrics(reg *metrics.Registry) Option {
+	return func(c *Cache) {
+		c.requests = reg.NewCounterVec("steem_cache_requests_total",
+			"Number of cacheable calls by method and result (hit, miss, shared).", "method", "result")
+	}
+}
+
+// New creates a new cache.
+func New(options ...Option) *Cache {
+	c := &Cache{
+		rules:      make(map[string]Rule, len(DefaultRules)),
+		maxEntries: DefaultMaxEntries,
+		entries:    make(map[string]*entry),
+		flights:    make(map[string]*flight),
+	}
+	for method, rule := range DefaultRules {
+		c.rules[method] = rule
+	}
+
+	for _, opt := range options {
+		opt(c)
+	}
+	return c
+}
+
+// Middleware returns the middleware serving cacheable calls from the cache.
+func (c *Cache) Middleware() middleware.Middleware {
+	return func(next interfaces.Caller) interfaces.Caller {
+		return middleware.CallerFunc(func(method string, params, response interface{}) error {
+			return c.call(next, method, params, response)
+		})
+	}
+}
+
+// SetHeadBlock informs the cache about the current head block number.
+// Block-scoped entries are dropped when the number changes.
+//
+// The head block number is also picked up automatically
+// from get_dynamic_global_properties responses.
+func (c *Cache) SetHeadBlock(num uint32) {
+	c.mu.Lock()
+	c.setHeadBlock(num)
+	c.mu.Unlock()
+}
+
+// Invalidate drops all cached entries.
+func (c *Cache) Invalidate() {
+	c.mu.Lock()
+	c.entries = make(map[string]*entry)
+	c.mu.Unlock()
+}
+
+// Stats returns the current cache statistics.
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+func (c *Cache) call(next interfaces.Caller, method string, params, response interface{}) error {
+	_, name := middleware.MethodName(method, params)
+	rule, ok := c.rules[name]
+	if !ok {
+		return next.Call(method, params, response)
+	}
+
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		return next.Call(method, params, response)
+	}
+	key := method + "\x00" + string(rawParams)
+
+	c.mu.Lock()
+	// Try the cache first.
+	if e, ok := c.entries[key]; ok {
+		if c.valid(e) {
+			c.stats.Hits++
+			c.mu.Unlock()
+			c.count(name, "hit")
+			return decode(e.data, response)
+		}
+		delete(c.entries, key)
+	}
+
+	// Join an identical call in progress.
+	if f, ok := c.flights[key]; ok {
+		c.stats.Shared++
+		c.mu.Unlock()
+		c.count(name, "shared")
+		f.wg.Wait()
+		if f.err != nil {
+			return f.err
+		}
+		return decode(f.data, response)
+	}
+
+	// Perform the call.
+	f := &flight{}
+	f.wg.Add(1)
+	c.flights[key] = f
+	c.stats.Misses++
+	c.mu.Unlock()
+	c.count(name, "miss")
+
+	f.err = next.Call(method, params, &f.data)
+
+	c.mu.Lock()
+	delete(c.flights, key)
+	if f.err == nil {
+		if name == "get_dynamic_global_properties" {
+			c.observeProperties(f.data)
+		}
+		c.store(key, rule, f.data)
+	}
+	c.mu.Unlock()
+	f.wg.Done()
+
+	if f.err != nil {
+		return f.err
+	}
+	return decode(f.data, response)
+}
+
+func (c *Cache) valid(e *entry) bool {
+	if e.rule.TTL != 0 && time.Now().After(e.expires) {
+		return false
+	}
+	if e.rule.Block && e.block != c.headBlock {
+		return false
+	}
+	return true
+}
+
+func (c *Cache) store(key string, rule Rule, data json.RawMessage) {
+	if len(c.entries) >= c.maxEntries {
+		for k, e := range c.entries {
+			if !c.valid(e) {
+				delete(c.entries, k)
+			}
+		}
+	}
+
+	c.entries[key] = &entry{
+		data:    data,
+		expires: time.Now().Add(rule.TTL),
+		block:   c.headBlock,
+		rule:    rule,
+	}
+}
+
+func (c *Cache) observeProperties(data json.RawMessage) {
+	var props struct {
+		HeadBlockNumber uint32 `json:"head_block_number"`
+	}
+	if err := json.Unmarshal(data, &props); err == nil && props.HeadBlockNumber != 0 {
+		c.setHeadBlock(props.HeadBlockNumber)
+	}
+}
+
+func (c *Cache) setHeadBlock(num uint32) {
+	if num == c.headBlock {
+		return
+	}
+	c.headBlock = num
+	for k, e := range c.entries {
+		if e.rule.Block {
+			delete(c.entries, k)
+		}
+	}
+}
+
+func (c *Cache) count(method, result string) {
+	if c.requests != nil {
+		c.requests.With(method, result).Inc()
+	}
+}
+
+func decode(data json.RawMessage, response interface{}) error {
+	if response == nil {
+		return nil
+	}
+	return json.Unmarshal(data, response)
+}
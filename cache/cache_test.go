@@ -0,0 +1,135 @@
+package cache
+
+import (
+	// Stdlib
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	// RPC
+	"github.com/asuleymanov/rpc/middleware"
+)
+
+type fakeNode struct {
+	calls     int32
+	headBlock uint32
+	release   chan struct{}
+}
+
+func (node *fakeNode) Call(method string, params, response interface{}) error {
+	atomic.AddInt32(&node.calls, 1)
+	if node.release != nil {
+		<-node.release
+	}
+
+	var result interface{}
+	switch method {
+	case "get_dynamic_global_properties":
+		result = map[string]interface{}{"head_block_number": atomic.LoadUint32(&node.headBlock)}
+	default:
+		result = []string{method}
+	}
+
+	if response == nil {
+		return nil
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, response)
+}
+
+func TestCacheHit(t *testing.T) {
+	node := &fakeNode{}
+	c := New()
+	caller := c.Middleware()(node)
+
+	for i := 0; i < 3; i++ {
+		var config []string
+		if err := caller.Call("get_config", []interface{}{}, &config); err != nil {
+			t.Fatal(err)
+		}
+		if len(config) != 1 || config[0] != "get_config" {
+			t.Fatalf("unexpected response: %v", config)
+		}
+	}
+
+	// Methods without a rule are never cached.
+	for i := 0; i < 2; i++ {
+		if err := caller.Call("get_block", []interface{}{1}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if calls := atomic.LoadInt32(&node.calls); calls != 3 {
+		t.Errorf("expected 3 calls to reach the node, got %v", calls)
+	}
+	if stats := c.Stats(); stats.Hits != 2 || stats.Misses != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestCacheBlockInvalidation(t *testing.T) {
+	node := &fakeNode{headBlock: 10}
+	c := New(SetRule("get_dynamic_global_properties", Rule{TTL: time.Hour, Block: true}))
+	caller := c.Middleware()(node)
+
+	get := func() uint32 {
+		var props struct {
+			HeadBlockNumber uint32 `json:"head_block_number"`
+		}
+		if err := caller.Call("get_dynamic_global_properties", []interface{}{}, &props); err != nil {
+			t.Fatal(err)
+		}
+		return props.HeadBlockNumber
+	}
+
+	if num := get(); num != 10 {
+		t.Fatalf("expected block 10, got %v", num)
+	}
+	atomic.StoreUint32(&node.headBlock, 11)
+	if num := get(); num != 10 {
+		t.Fatalf("expected cached block 10, got %v", num)
+	}
+
+	c.SetHeadBlock(11)
+	if num := get(); num != 11 {
+		t.Fatalf("expected block 11, got %v", num)
+	}
+}
+
+func TestCacheSingleflight(t *testing.T) {
+	node := &fakeNode{release: make(chan struct{})}
+	c := New()
+	caller := middleware.Chain(c.Middleware())(node)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var config []string
+			if err := caller.Call("get_config", []interface{}{}, &config); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	// Wait for all the goroutines to either call the node or join the flight.
+	for {
+		stats := c.Stats()
+		if stats.Misses+stats.Shared == 5 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(node.release)
+	wg.Wait()
+
+	if calls := atomic.LoadInt32(&node.calls); calls != 1 {
+		t.Errorf("expected 1 call to reach the node, got %v", calls)
+	}
+}
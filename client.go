@@ -1,21 +1,65 @@
 package rpc
 
 import (
+	// Stdlib
+	"strings"
+
 	// RPC
+	"github.com/asuleymanov/rpc/apis/accountbykey"
+	"github.com/asuleymanov/rpc/apis/accounthistory"
+	"github.com/asuleymanov/rpc/apis/block"
 	"github.com/asuleymanov/rpc/apis/database"
 	"github.com/asuleymanov/rpc/apis/follow"
 	"github.com/asuleymanov/rpc/apis/login"
 	"github.com/asuleymanov/rpc/apis/market"
 	"github.com/asuleymanov/rpc/apis/networkbroadcast"
+	"github.com/asuleymanov/rpc/apis/witness"
 	"github.com/asuleymanov/rpc/interfaces"
+	internalrpc "github.com/asuleymanov/rpc/internal/rpc"
+
+	// Vendor
+	"github.com/pkg/errors"
 )
 
+// ErrAPIUnavailable is returned by API methods when the node does not expose the API,
+// use errors.Cause to check for it.
+var ErrAPIUnavailable = internalrpc.ErrAPIUnavailable
+
+// Protocol selects how the calls are encoded.
+type Protocol int
+
+const (
+	// ProtocolLegacy sends calls as "call" [api_id, method, params],
+	// with the numeric API IDs resolved using get_api_by_name.
+	ProtocolLegacy Protocol = iota
+
+	// ProtocolAppbase sends calls using dotted method names, e.g. condenser_api.get_accounts,
+	// as expected by appbase nodes.
+	ProtocolAppbase
+)
+
+// Option represents an option that can be passed into the client constructor.
+type Option func(*Client)
+
+// SetProtocol can be used to select the protocol spoken by the node,
+// the default being ProtocolLegacy.
+func SetProtocol(protocol Protocol) Option {
+	return func(client *Client) {
+		client.protocol = protocol
+	}
+}
+
 // Client can be used to access Steem remote APIs.
 //
 // There is a public field for every Steem API available,
 // e.g. Client.Database corresponds to database_api.
+//
+// APIs are resolved lazily on first use, so the client can be used
+// with nodes that only expose a subset of the APIs.
 type Client struct {
-	cc interfaces.CallCloser
+	cc       interfaces.CallCloser
+	caller   interfaces.Caller
+	protocol Protocol
 
 	// Login represents login_api.
 	Login *login.API
@@ -31,41 +75,132 @@ type Client struct {
 
 	// NetworkBroadcast represents network_broadcast_api.
 	NetworkBroadcast *networkbroadcast.API
+
+	// AccountByKey represents account_by_key_api.
+	AccountByKey *accountbykey.API
+
+	// Witness represents witness_api.
+	Witness *witness.API
+
+	// Block represents block_api, available on appbase nodes only.
+	Block *block.API
+
+	// AccountHistory represents account_history_api, available on appbase nodes only.
+	AccountHistory *accounthistory.API
 }
 
 // NewClient creates a new RPC client that use the given CallCloser internally.
-func NewClient(cc interfaces.CallCloser) (*Client, error) {
+func NewClient(cc interfaces.CallCloser, options ...Option) (*Client, error) {
 	client := &Client{cc: cc}
+	for _, opt := range options {
+		opt(client)
+	}
+
+	// The same API methods work with both node generations,
+	// the calls are translated in case the node speaks appbase.
+	var appbaseCaller interfaces.Caller
+	switch client.protocol {
+	case ProtocolLegacy:
+		client.caller = cc
+		appbaseCaller = internalrpc.NewLegacyCaller(cc)
+	case ProtocolAppbase:
+		client.caller = internalrpc.NewAppbaseCaller(cc)
+		appbaseCaller = cc
+	default:
+		return nil, errors.Errorf("unknown protocol: %v", client.protocol)
+	}
 
-	loginAPI, err := login.NewAPI(client.cc)
+	loginAPI, err := login.NewAPI(client.caller)
 	if err != nil {
 		return nil, err
 	}
 	client.Login = loginAPI
 
-	client.Database = database.NewAPI(client.cc)
+	client.Database = database.NewAPI(client.caller)
 
-	followAPI, err := follow.NewAPI(client.cc)
+	followAPI, err := follow.NewAPI(client.caller)
 	if err != nil {
 		return nil, err
 	}
 	client.Follow = followAPI
 
-	marketAPI, err := market.NewAPI(client.cc)
+	marketAPI, err := market.NewAPI(client.caller)
 	if err != nil {
 		return nil, err
 	}
 	client.Market = marketAPI
 
-	networkBroadcastAPI, err := networkbroadcast.NewAPI(client.cc)
+	networkBroadcastAPI, err := networkbroadcast.NewAPI(client.caller)
 	if err != nil {
 		return nil, err
 	}
 	client.NetworkBroadcast = networkBroadcastAPI
 
+	accountByKeyAPI, err := accountbykey.NewAPI(client.caller)
+	if err != nil {
+		return nil, err
+	}
+	client.AccountByKey = accountByKeyAPI
+
+	witnessAPI, err := witness.NewAPI(client.caller)
+	if err != nil {
+		return nil, err
+	}
+	client.Witness = witnessAPI
+
+	client.Block = block.NewAPI(appbaseCaller)
+	client.AccountHistory = accounthistory.NewAPI(appbaseCaller)
+
 	return client, nil
 }
 
+// AvailableAPIs asks the node which APIs it exposes.
+//
+// For legacy nodes only the APIs known to the client are probed,
+// appbase nodes list all their methods.
+func (client *Client) AvailableAPIs() ([]string, error) {
+	if client.protocol == ProtocolAppbase {
+		var methods []string
+		if err := client.cc.Call("jsonrpc.get_methods", struct{}{}, &methods); err != nil {
+			return nil, err
+		}
+		var available []string
+		seen := make(map[string]bool)
+		for _, method := range methods {
+			name := strings.SplitN(method, ".", 2)[0]
+			if !seen[name] {
+				seen[name] = true
+				available = append(available, name)
+			}
+		}
+		return available, nil
+	}
+
+	names := []string{
+		login.APIID,
+		database.APIID,
+		follow.APIID,
+		market.APIID,
+		networkbroadcast.APIID,
+		accountbykey.APIID,
+		witness.APIID,
+		block.APIID,
+		accounthistory.APIID,
+	}
+
+	var available []string
+	for _, name := range names {
+		_, err := internalrpc.GetNumericAPIID(client.cc, name)
+		switch {
+		case err == nil:
+			available = append(available, name)
+		case errors.Cause(err) != ErrAPIUnavailable:
+			return nil, err
+		}
+	}
+	return available, nil
+}
+
 // Close should be used to close the client when no longer needed.
 // It simply calls Close() on the underlying CallCloser.
 func (client *Client) Close() error {
@@ -0,0 +1,316 @@
+package client
+
+import (
+	// Stdlib
+	"math/big"
+
+	// Vendor
+	"github.com/pkg/errors"
+
+	// RPC
+	"github.com/asuleymanov/rpc/apis/database"
+	"github.com/asuleymanov/rpc/encoding/wif"
+	"github.com/asuleymanov/rpc/types"
+)
+
+// Authority roles of an account, also the names of the keys in OpTypeKey.
+const (
+	RoleOwner   = "owner"
+	RoleActive  = "active"
+	RolePosting = "posting"
+)
+
+const (
+	// CreateAccountWithSteemModifier is STEEMIT_CREATE_ACCOUNT_WITH_STEEM_MODIFIER:
+	// account_create costs this many times the median account creation fee.
+	CreateAccountWithSteemModifier = 30
+	// CreateAccountDelegationRatio is STEEMIT_CREATE_ACCOUNT_DELEGATION_RATIO: with account_create_with_delegation
+	// this much STEEM of delegated Steem Power replaces one STEEM of the fee.
+	CreateAccountDelegationRatio = 5
+)
+
+// ErrAccountExists is returned when creating an account with a name already taken.
+var ErrAccountExists = errors.New("account already exists")
+
+// ErrAccountLockout is returned for authority updates nobody would be able to sign for.
+var ErrAccountLockout = errors.New("the authority cannot be satisfied, the update would lock the account out")
+
+// NewAccount is an account to create.
+type NewAccount struct {
+	Name         string
+	Owner        *types.Authority
+	Active       *types.Authority
+	Posting      *types.Authority
+	MemoKey      string
+	JSONMetadata string
+}
+
+// NewAccountWithKeys returns an account with a single public key for every role.
+func NewAccountWithKeys(name, ownerKey, activeKey, postingKey, memoKey string) *NewAccount {
+	return &NewAccount{
+		Name:    name,
+		Owner:   types.NewAuthority(ownerKey),
+		Active:  types.NewAuthority(activeKey),
+		Posting: types.NewAuthority(postingKey),
+		MemoKey: memoKey,
+	}
+}
+
+// Validate checks the name, the authorities and the memo key of the account.
+func (acc *NewAccount) Validate() error {
+	if err := ValidateAccountName(acc.Name); err != nil {
+		return err
+	}
+	for role, auth := range map[string]*types.Authority{RoleOwner: acc.Owner, RoleActive: acc.Active, RolePosting: acc.Posting} {
+		if auth == nil {
+			return errors.Errorf("new account: missing %v authority", role)
+		}
+		if err := auth.Validate(); err != nil {
+			return errors.Wrapf(err, "new account: invalid %v authority", role)
+		}
+	}
+	if _, err := wif.DecodePublicKey(acc.MemoKey); err != nil {
+		return errors.Wrap(err, "new account: invalid memo key")
+	}
+	return nil
+}
+
+// ValidateAccountName checks the name the way steemd does, see types.ValidateAccountName.
+func ValidateAccountName(name string) error {
+	return types.ValidateAccountName(name)
+}
+
+// AccountCreationFee returns the fee of account_create: the median account creation fee
+// of the witnesses times CreateAccountWithSteemModifier.
+func (api *Client) AccountCreationFee() (*types.Asset, error) {
+	median, err := api.medianCreationFee()
+	if err != nil {
+		return nil, err
+	}
+	return &types.Asset{
+		Amount:    median.Amount * CreateAccountWithSteemModifier,
+		Precision: median.Precision,
+		Symbol:    median.Symbol,
+	}, nil
+}
+
+// AccountCreationDelegation returns the VESTS to delegate with account_create_with_delegation
+// paying the given fee, see CreationDelegation.
+func (api *Client) AccountCreationDelegation(fee *types.Asset) (*types.Asset, error) {
+	median, err := api.medianCreationFee()
+	if err != nil {
+		return nil, err
+	}
+	props, err := api.Rpc.Database.GetDynamicGlobalProperties()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error get DynamicGlobalProperties: ")
+	}
+	return CreationDelegation(median, fee, props)
+}
+
+// CreationDelegation returns the VESTS the creator must delegate with account_create_with_delegation
+// paying the given fee, at least the median account creation fee. The fee counts CreateAccountDelegationRatio
+// times towards the delegation, which must be worth the fee of account_create that many times over.
+// The STEEM are converted to VESTS at the current vesting share price rounding the way steemd does.
+func CreationDelegation(median, fee *types.Asset, props *database.DynamicGlobalProperties) (*types.Asset, error) {
+	if fee.Symbol != median.Symbol || fee.Precision != median.Precision {
+		return nil, errors.Errorf("fee %v must be in %v", fee, median.Symbol)
+	}
+	if fee.Amount < median.Amount {
+		return nil, errors.Errorf("fee %v is less than the account creation fee %v", fee, median)
+	}
+	shares, err := parseAsset(props.TotalVestingShares)
+	if err != nil {
+		return nil, err
+	}
+	fund, err := parseAsset(props.TotalVersingFundSteem)
+	if err != nil {
+		return nil, err
+	}
+	if fund.Amount == 0 {
+		return nil, errors.New("total vesting fund is zero")
+	}
+
+	toVests := func(steem int64) *big.Int {
+		vests := new(big.Int).Mul(big.NewInt(steem), big.NewInt(shares.Amount))
+		return vests.Quo(vests, big.NewInt(fund.Amount))
+	}
+	target := toVests(median.Amount * CreateAccountWithSteemModifier * CreateAccountDelegationRatio)
+	delegation := target.Sub(target, toVests(fee.Amount*CreateAccountDelegationRatio))
+	if delegation.Sign() < 0 {
+		delegation.SetInt64(0)
+	}
+	return &types.Asset{Amount: delegation.Int64(), Precision: shares.Precision, Symbol: shares.Symbol}, nil
+}
+
+// AccountCreate creates the account paying the fee returned by AccountCreationFee.
+func (api *Client) AccountCreate(creator string, account *NewAccount) (*BResp, error) {
+	if err := api.checkNewAccount(account); err != nil {
+		return nil, err
+	}
+	fee, err := api.AccountCreationFee()
+	if err != nil {
+		return nil, err
+	}
+	tx := &types.AccountCreateOperation{
+		Fee:            fee.String(),
+		Creator:        creator,
+		NewAccountName: account.Name,
+		Owner:          account.Owner,
+		Active:         account.Active,
+		Posting:        account.Posting,
+		MemoKey:        account.MemoKey,
+		JsonMetadata:   account.JSONMetadata,
+	}
+	resp, err := api.Send_Trx(creator, tx)
+	return resp, errors.Wrapf(err, "Error AccountCreate: ")
+}
+
+// AccountCreateWithDelegation creates the account paying the given fee and delegating
+// the VESTS returned by CreationDelegation. A nil fee pays the median account creation fee,
+// the creator can take the delegation back after STEEMIT_CREATE_ACCOUNT_DELEGATION_TIME.
+func (api *Client) AccountCreateWithDelegation(creator string, account *NewAccount, fee *types.Asset) (*BResp, error) {
+	if err := api.checkNewAccount(account); err != nil {
+		return nil, err
+	}
+	if fee == nil {
+		median, err := api.medianCreationFee()
+		if err != nil {
+			return nil, err
+		}
+		fee = median
+	}
+	delegation, err := api.AccountCreationDelegation(fee)
+	if err != nil {
+		return nil, err
+	}
+	tx := &types.AccountCreateWithDelegationOperation{
+		Fee:            fee.String(),
+		Delegation:     delegation.String(),
+		Creator:        creator,
+		NewAccountName: account.Name,
+		Owner:          account.Owner,
+		Active:         account.Active,
+		Posting:        account.Posting,
+		MemoKey:        account.MemoKey,
+		JsonMetadata:   account.JSONMetadata,
+		Extensions:     []interface{}{},
+	}
+	resp, err := api.Send_Trx(creator, tx)
+	return resp, errors.Wrapf(err, "Error AccountCreateWithDelegation: ")
+}
+
+// UpdateAuthority changes the owner, active or posting authority of the account.
+// The update function gets a copy of the current authority to change with the types.Authority methods,
+// the result is checked with CheckAuthority before it is broadcast. The other authorities,
+// the memo key and the metadata stay as they are. Changing the owner authority is signed with the owner key.
+func (api *Client) UpdateAuthority(username, role string, update func(auth *types.Authority) error) (*BResp, error) {
+	acc, err := api.getAccount(username)
+	if err != nil {
+		return nil, err
+	}
+	current, err := accountAuthority(acc, role)
+	if err != nil {
+		return nil, err
+	}
+	auth := current.Clone()
+	if err := update(auth); err != nil {
+		return nil, err
+	}
+	if err := api.CheckAuthority(username, auth); err != nil {
+		return nil, err
+	}
+
+	tx := &types.AccountUpdateOperation{
+		Account:      username,
+		MemoKey:      acc.MemoKey,
+		JsonMetadata: acc.JSONMetadata,
+	}
+	switch role {
+	case RoleOwner:
+		tx.Owner = auth
+	case RoleActive:
+		tx.Active = auth
+	case RolePosting:
+		tx.Posting = auth
+	}
+	resp, err := api.Send_Trx(username, tx)
+	return resp, errors.Wrapf(err, "Error AccountUpdate: ")
+}
+
+// CheckAuthority validates an authority for the account and makes sure it can still be satisfied:
+// the weights of its keys and of the existing accounts other than the account itself
+// must reach the threshold, otherwise ErrAccountLockout is returned.
+func (api *Client) CheckAuthority(username string, auth *types.Authority) error {
+	if err := auth.Validate(); err != nil {
+		return err
+	}
+
+	var names []string
+	for name := range auth.AccountAuths {
+		if name != username {
+			names = append(names, name)
+		}
+	}
+	var signers []string
+	if len(names) != 0 {
+		accounts, err := api.Rpc.Database.GetAccounts(names)
+		if err != nil {
+			return errors.Wrapf(err, "Error GetAccounts: ")
+		}
+		for _, acc := range accounts {
+			if acc != nil {
+				signers = append(signers, acc.Name)
+			}
+		}
+	}
+	keys := make([]string, 0, len(auth.KeyAuths))
+	for key := range auth.KeyAuths {
+		keys = append(keys, key)
+	}
+	if !auth.SatisfiedBy(keys, signers) {
+		return ErrAccountLockout
+	}
+	return nil
+}
+
+func (api *Client) checkNewAccount(account *NewAccount) error {
+	if err := account.Validate(); err != nil {
+		return err
+	}
+	exists, err := api.AccountExists(account.Name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.Wrap(ErrAccountExists, account.Name)
+	}
+	return nil
+}
+
+func (api *Client) medianCreationFee() (*types.Asset, error) {
+	props, err := api.Rpc.Database.GetChainProperties()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error GetChainProperties: ")
+	}
+	fee, err := types.ParseAsset(props.AccountCreationFee)
+	return fee, errors.Wrap(err, "invalid account creation fee")
+}
+
+func accountAuthority(acc *database.Account, role string) (*types.Authority, error) {
+	var auth *types.Authority
+	switch role {
+	case RoleOwner:
+		auth = acc.Owner
+	case RoleActive:
+		auth = acc.Active
+	case RolePosting:
+		auth = acc.Posting
+	default:
+		return nil, errors.Errorf("unknown authority role %q", role)
+	}
+	if auth == nil {
+		return nil, errors.Errorf("account %v has no %v authority", acc.Name, role)
+	}
+	return auth, nil
+}
@@ -0,0 +1,155 @@
+package client
+
+import (
+	// Stdlib
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	// RPC
+	"github.com/asuleymanov/rpc"
+	"github.com/asuleymanov/rpc/apis/database"
+	"github.com/asuleymanov/rpc/types"
+)
+
+const (
+	testKey1 = "STM6MRyAjQq8ud7hVNYcfnVPJqcVpscN5So8BhtHuGYqET5GDW5CV"
+	testKey2 = "STM7jNh5ejQoqHqWcGWFJ1v4F5CzsG3EiBuz1VooCng1cH5QpJD27"
+)
+
+// accountsThread serves get_accounts for the existing accounts and the chain properties.
+type accountsThread struct {
+	accounts map[string]bool
+}
+
+func (thread *accountsThread) Call(method string, params, response interface{}) error {
+	var result interface{}
+	switch method {
+	case "get_accounts":
+		var accounts []interface{}
+		for _, name := range params.([][]string)[0] {
+			if thread.accounts[name] {
+				accounts = append(accounts, map[string]interface{}{"name": name})
+			}
+		}
+		result = accounts
+	case "get_chain_properties":
+		result = map[string]interface{}{"account_creation_fee": "0.100 STEEM"}
+	default:
+		return fmt.Errorf("unexpected method %v", method)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, response)
+}
+
+func (thread *accountsThread) Close() error {
+	return nil
+}
+
+func TestCreationDelegation(t *testing.T) {
+	props := &database.DynamicGlobalProperties{
+		TotalVestingShares:    "400000000.000000 VESTS",
+		TotalVersingFundSteem: "200000.000 STEEM",
+	}
+	median := asset(t, "0.100 STEEM")
+
+	for fee, expected := range map[string]string{
+		"0.100 STEEM": "29000.000000 VESTS",
+		"1.000 STEEM": "20000.000000 VESTS",
+		"3.000 STEEM": "0.000000 VESTS",
+		"5.000 STEEM": "0.000000 VESTS",
+	} {
+		delegation, err := CreationDelegation(median, asset(t, fee), props)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := delegation.String(); got != expected {
+			t.Errorf("fee %v: expected %v, got %v", fee, expected, got)
+		}
+	}
+
+	if _, err := CreationDelegation(median, asset(t, "0.050 STEEM"), props); err == nil {
+		t.Error("expected an error for a fee below the account creation fee")
+	}
+}
+
+func TestAccountCreation(t *testing.T) {
+	client, err := rpc.NewClient(&accountsThread{accounts: map[string]bool{"alice": true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := &Client{Rpc: client}
+
+	fee, err := api.AccountCreationFee()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fee.String(); got != "3.000 STEEM" {
+		t.Errorf("expected 3.000 STEEM, got %v", got)
+	}
+
+	account := NewAccountWithKeys("alice", testKey1, testKey1, testKey2, testKey2)
+	if err := account.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := api.checkNewAccount(account); err == nil {
+		t.Error("expected an error for an existing account")
+	}
+	account.Name = "carol"
+	if err := api.checkNewAccount(account); err != nil {
+		t.Error(err)
+	}
+	account.MemoKey = "STM1111"
+	if err := account.Validate(); err == nil {
+		t.Error("expected an error for an invalid memo key")
+	}
+}
+
+func TestCheckAuthority(t *testing.T) {
+	client, err := rpc.NewClient(&accountsThread{accounts: map[string]bool{"alice": true, "bob": true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := &Client{Rpc: client}
+
+	multisig := func(account string) *types.Authority {
+		auth := types.NewAuthority(testKey1)
+		if err := auth.AddAccount(account, 1); err != nil {
+			t.Fatal(err)
+		}
+		if err := auth.SetThreshold(2); err != nil {
+			t.Fatal(err)
+		}
+		return auth
+	}
+
+	if err := api.CheckAuthority("alice", multisig("bob")); err != nil {
+		t.Errorf("bob: %v", err)
+	}
+	// Neither a missing account nor the account itself can sign for it.
+	for _, account := range []string{"ghost", "alice"} {
+		if err := api.CheckAuthority("alice", multisig(account)); err != ErrAccountLockout {
+			t.Errorf("%v: expected ErrAccountLockout, got %v", account, err)
+		}
+	}
+
+	empty := types.NewAuthority(testKey1)
+	empty.RemoveKey(testKey1)
+	if err := api.CheckAuthority("alice", empty); err == nil {
+		t.Error("expected an error for an authority without keys")
+	}
+}
+
+func TestOperationKeys(t *testing.T) {
+	update := &types.AccountUpdateOperation{Account: "alice", Active: types.NewAuthority(testKey1)}
+	if keys := operationKeys(update); len(keys) != 1 || keys[0] != RoleActive {
+		t.Errorf("expected the active key, got %v", keys)
+	}
+	update.Owner = types.NewAuthority(testKey1)
+	if keys := operationKeys(update); len(keys) != 1 || keys[0] != RoleOwner {
+		t.Errorf("expected the owner key, got %v", keys)
+	}
+}
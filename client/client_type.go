@@ -5,8 +5,20 @@ type Beneficiarie struct {
 	Weight  uint16
 }
 
+// Payout modes accepted in PC_Options.Percent.
+const (
+	// PayoutDeclined declines the payout.
+	PayoutDeclined uint16 = 0
+	// Payout50 pays 50% in SBD and 50% in Steem Power.
+	Payout50 uint16 = 50
+	// PayoutPowerUp pays 100% in Steem Power.
+	PayoutPowerUp uint16 = 100
+)
+
 type PC_Options struct {
-	Percent   uint16
+	// Percent is one of the payout modes.
+	Percent uint16
+	// BenefList are the beneficiaries, they are sorted by account before broadcasting.
 	BenefList []Beneficiarie
 }
 
@@ -0,0 +1,73 @@
+package client
+
+import (
+	// Vendor
+	"github.com/pkg/errors"
+
+	// RPC
+	"github.com/asuleymanov/rpc/types"
+)
+
+// Maximum accepted payout of the payout modes other than PayoutDeclined.
+const maxAcceptedPayout = "1000000.000 SBD"
+
+// commentOptions builds the comment_options operation for the options,
+// the beneficiaries are validated and checked to exist.
+func (api *Client) commentOptions(author, permlink string, o *PC_Options) (*types.CommentOptionsOperation, error) {
+	op := &types.CommentOptionsOperation{
+		Author:               author,
+		Permlink:             permlink,
+		MaxAcceptedPayout:    maxAcceptedPayout,
+		AllowVotes:           true,
+		AllowCurationRewards: true,
+		Extensions:           types.CommentOptionsExtensions{},
+	}
+
+	switch o.Percent {
+	case PayoutDeclined:
+		op.MaxAcceptedPayout = "0.000 SBD"
+		op.PercentSteemDollars = types.Percent100
+	case Payout50:
+		op.PercentSteemDollars = types.Percent100
+	case PayoutPowerUp:
+		op.PercentSteemDollars = 0
+	default:
+		return nil, errors.Errorf("unknown payout mode %v", o.Percent)
+	}
+
+	if len(o.BenefList) > 0 {
+		benef := &types.CommentPayoutBeneficiaries{}
+		for _, val := range o.BenefList {
+			benef.Beneficiaries = append(benef.Beneficiaries, types.Beneficiarie{Account: val.Account, Weight: val.Weight})
+		}
+		benef.Sort()
+		if err := api.validateBeneficiaries(benef); err != nil {
+			return nil, err
+		}
+		op.Extensions = append(op.Extensions, benef)
+	}
+	return op, nil
+}
+
+// validateBeneficiaries checks the beneficiaries against the chain rules
+// and that all the accounts exist.
+func (api *Client) validateBeneficiaries(benef *types.CommentPayoutBeneficiaries) error {
+	if err := benef.Validate(); err != nil {
+		return err
+	}
+
+	names := make([]string, len(benef.Beneficiaries))
+	for i, ben := range benef.Beneficiaries {
+		names[i] = ben.Account
+	}
+	accounts, err := api.Rpc.Database.LookupAccountNames(names)
+	if err != nil {
+		return errors.Wrapf(err, "Error LookupAccountNames: ")
+	}
+	for i, name := range names {
+		if i >= len(accounts) || accounts[i] == nil || accounts[i].Name == "" {
+			return errors.Errorf("beneficiaries: account %v does not exist", name)
+		}
+	}
+	return nil
+}
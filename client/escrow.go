@@ -0,0 +1,376 @@
+package client
+
+import (
+	// Stdlib
+	"time"
+
+	// Vendor
+	"github.com/pkg/errors"
+
+	// RPC
+	"github.com/asuleymanov/rpc/apis/database"
+	"github.com/asuleymanov/rpc/types"
+)
+
+// ErrEscrowNotFound is returned for escrows that do not exist,
+// either never created or already released, rejected or expired unratified.
+var ErrEscrowNotFound = errors.New("escrow not found")
+
+// ErrEscrowNotAllowed is returned when an account may not perform the escrow action in the current state.
+var ErrEscrowNotAllowed = errors.New("escrow action not allowed")
+
+// EscrowState is the stage of the escrow lifecycle.
+type EscrowState int
+
+const (
+	// EscrowPending waits for the approval of the receiver and the agent.
+	EscrowPending EscrowState = iota
+	// EscrowUnratified passed the ratification deadline without both approvals,
+	// the chain returns the funds to the sender.
+	EscrowUnratified
+	// EscrowRatified was approved by the receiver and the agent, the sender and the receiver
+	// can release the funds to each other or open a dispute.
+	EscrowRatified
+	// EscrowDisputed can only be released by the agent.
+	EscrowDisputed
+	// EscrowExpired passed the expiration, the sender and the receiver can release the funds to either of them.
+	EscrowExpired
+)
+
+func (state EscrowState) String() string {
+	switch state {
+	case EscrowPending:
+		return "pending"
+	case EscrowUnratified:
+		return "unratified"
+	case EscrowRatified:
+		return "ratified"
+	case EscrowDisputed:
+		return "disputed"
+	case EscrowExpired:
+		return "expired"
+	default:
+		return "unknown"
+	}
+}
+
+// Escrow is an escrow transfer held by an agent.
+type Escrow struct {
+	ID                   uint32
+	From                 string
+	To                   string
+	Agent                string
+	RatificationDeadline time.Time
+	Expiration           time.Time
+	SBD                  *types.Asset
+	Steem                *types.Asset
+	// PendingFee is paid to the agent when it approves.
+	PendingFee    *types.Asset
+	ToApproved    bool
+	AgentApproved bool
+	Disputed      bool
+}
+
+// NewEscrow converts the escrow object returned by get_escrow.
+func NewEscrow(escrow *database.Escrow) (*Escrow, error) {
+	e := &Escrow{
+		ID:            escrow.EscrowID,
+		From:          escrow.From,
+		To:            escrow.To,
+		Agent:         escrow.Agent,
+		ToApproved:    escrow.ToApproved,
+		AgentApproved: escrow.AgentApproved,
+		Disputed:      escrow.Disputed,
+	}
+	if t := escrow.RatificationDeadline; t != nil && t.Time != nil {
+		e.RatificationDeadline = *t.Time
+	}
+	if t := escrow.EscrowExpiration; t != nil && t.Time != nil {
+		e.Expiration = *t.Time
+	}
+
+	var err error
+	if e.SBD, err = parseBalance(escrow.SbdBalance, types.SymbolSBD); err != nil {
+		return nil, err
+	}
+	if e.Steem, err = parseBalance(escrow.SteemBalance, types.SymbolSteem); err != nil {
+		return nil, err
+	}
+	if e.PendingFee, err = parseBalance(escrow.PendingFee, types.SymbolSteem); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Ratified reports whether both the receiver and the agent approved the escrow.
+func (e *Escrow) Ratified() bool {
+	return e.ToApproved && e.AgentApproved
+}
+
+// State returns the state of the escrow at the given chain time.
+func (e *Escrow) State(now time.Time) EscrowState {
+	switch {
+	case !e.Ratified() && !now.Before(e.RatificationDeadline):
+		return EscrowUnratified
+	case !e.Ratified():
+		return EscrowPending
+	case e.Disputed:
+		return EscrowDisputed
+	case !now.Before(e.Expiration):
+		return EscrowExpired
+	default:
+		return EscrowRatified
+	}
+}
+
+// CanApprove checks whether who may approve or reject the escrow at the given time.
+func (e *Escrow) CanApprove(who string, now time.Time) error {
+	if who != e.To && who != e.Agent {
+		return errors.Wrapf(ErrEscrowNotAllowed, "%v is neither the receiver nor the agent", who)
+	}
+	if state := e.State(now); state != EscrowPending {
+		return errors.Wrapf(ErrEscrowNotAllowed, "the escrow is %v", state)
+	}
+	if (who == e.To && e.ToApproved) || (who == e.Agent && e.AgentApproved) {
+		return errors.Wrapf(ErrEscrowNotAllowed, "%v already approved the escrow", who)
+	}
+	return nil
+}
+
+// CanDispute checks whether who may open a dispute at the given time.
+func (e *Escrow) CanDispute(who string, now time.Time) error {
+	if who != e.From && who != e.To {
+		return errors.Wrapf(ErrEscrowNotAllowed, "%v is neither the sender nor the receiver", who)
+	}
+	if state := e.State(now); state != EscrowRatified {
+		return errors.Wrapf(ErrEscrowNotAllowed, "the escrow is %v", state)
+	}
+	return nil
+}
+
+// CanRelease checks whether who may release the given amounts to the receiver at the given time.
+//
+// Before the expiration the sender can only release to the receiver and the receiver only
+// to the sender, after it both can release to either. A disputed escrow is released by the agent.
+func (e *Escrow) CanRelease(who, receiver string, sbd, steem *types.Asset, now time.Time) error {
+	if who != e.From && who != e.To && who != e.Agent {
+		return errors.Wrapf(ErrEscrowNotAllowed, "%v is not a party of the escrow", who)
+	}
+	if receiver != e.From && receiver != e.To {
+		return errors.Wrapf(ErrEscrowNotAllowed, "%v is neither the sender nor the receiver", receiver)
+	}
+	if err := checkEscrowAmounts(sbd, steem); err != nil {
+		return err
+	}
+	if sbd.Amount > e.SBD.Amount || steem.Amount > e.Steem.Amount {
+		return errors.Errorf("the escrow only holds %v and %v", e.SBD, e.Steem)
+	}
+
+	switch state := e.State(now); state {
+	case EscrowDisputed:
+		if who != e.Agent {
+			return errors.Wrap(ErrEscrowNotAllowed, "only the agent can release a disputed escrow")
+		}
+	case EscrowRatified:
+		if who == e.Agent {
+			return errors.Wrap(ErrEscrowNotAllowed, "the agent can only release a disputed escrow")
+		}
+		if (who == e.From && receiver != e.To) || (who == e.To && receiver != e.From) {
+			return errors.Wrapf(ErrEscrowNotAllowed, "%v can only release to the other party before the expiration", who)
+		}
+	case EscrowExpired:
+		if who == e.Agent {
+			return errors.Wrap(ErrEscrowNotAllowed, "the agent can only release a disputed escrow")
+		}
+	default:
+		return errors.Wrapf(ErrEscrowNotAllowed, "the escrow is %v", state)
+	}
+	return nil
+}
+
+// EscrowRequest describes a new escrow transfer.
+type EscrowRequest struct {
+	From  string
+	To    string
+	Agent string
+	// ID identifies the escrow among the escrows of the sender,
+	// zero selects one from the current time.
+	ID    uint32
+	SBD   *types.Asset
+	Steem *types.Asset
+	// Fee is paid to the agent when it approves, in STEEM or SBD.
+	Fee *types.Asset
+	// RatificationDeadline is the time until which the receiver and the agent must approve.
+	RatificationDeadline time.Time
+	// Expiration is the time after which either party can release the funds.
+	Expiration time.Time
+	Meta       string
+}
+
+// Validate checks the request the way the chain does.
+func (req *EscrowRequest) Validate(now time.Time) error {
+	if req.From == "" || req.To == "" || req.Agent == "" {
+		return errors.New("the sender, the receiver and the agent are required")
+	}
+	if req.Agent == req.From || req.Agent == req.To {
+		return errors.New("the agent must be different from the sender and the receiver")
+	}
+	if req.Fee == nil || req.Fee.Amount < 0 || (req.Fee.Symbol != types.SymbolSteem && req.Fee.Symbol != types.SymbolSBD) {
+		return errors.New("the fee must be a non-negative STEEM or SBD amount")
+	}
+	if err := checkEscrowAmounts(req.SBD, req.Steem); err != nil {
+		return err
+	}
+	if !req.RatificationDeadline.After(now) {
+		return errors.New("the ratification deadline must be in the future")
+	}
+	if !req.Expiration.After(req.RatificationDeadline) {
+		return errors.New("the expiration must be after the ratification deadline")
+	}
+	return nil
+}
+
+func checkEscrowAmounts(sbd, steem *types.Asset) error {
+	if sbd == nil || sbd.Symbol != types.SymbolSBD || sbd.Amount < 0 {
+		return errors.New("the SBD amount must be a non-negative SBD amount")
+	}
+	if steem == nil || steem.Symbol != types.SymbolSteem || steem.Amount < 0 {
+		return errors.New("the STEEM amount must be a non-negative STEEM amount")
+	}
+	if sbd.Amount == 0 && steem.Amount == 0 {
+		return errors.New("the escrow must transfer a non-zero amount")
+	}
+	return nil
+}
+
+// GetEscrow returns the escrow of the sender with the given ID.
+func (api *Client) GetEscrow(from string, id uint32) (*Escrow, error) {
+	escrow, err := api.Rpc.Database.GetEscrow(from, id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error GetEscrow: ")
+	}
+	if escrow == nil {
+		return nil, errors.Wrapf(ErrEscrowNotFound, "%v/%v", from, id)
+	}
+	return NewEscrow(escrow)
+}
+
+// EscrowTransfer creates an escrow, the request's ID is set when it was zero.
+func (api *Client) EscrowTransfer(req *EscrowRequest) (*BResp, error) {
+	now, err := api.headTime()
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Validate(now); err != nil {
+		return nil, err
+	}
+	if req.ID == 0 {
+		req.ID = uint32(now.Unix())
+	}
+
+	// The node checks the signature against the JSON encoding, which has no time zone.
+	deadline, expiration := req.RatificationDeadline.UTC(), req.Expiration.UTC()
+	tx := &types.EscrowTransferOperation{
+		From:                 req.From,
+		To:                   req.To,
+		Agent:                req.Agent,
+		EscrowId:             req.ID,
+		SbdAmount:            req.SBD.String(),
+		SteemAmount:          req.Steem.String(),
+		Fee:                  req.Fee.String(),
+		JsonMeta:             req.Meta,
+		RatificationDeadline: &types.Time{Time: &deadline},
+		EscrowExpiration:     &types.Time{Time: &expiration},
+	}
+	resp, err := api.Send_Trx(req.From, tx)
+	return resp, errors.Wrapf(err, "Error EscrowTransfer: ")
+}
+
+// EscrowApprove approves or rejects the escrow as its receiver or agent.
+// A rejection by either of them returns the funds to the sender.
+func (api *Client) EscrowApprove(from string, id uint32, who string, approve bool) (*BResp, error) {
+	escrow, now, err := api.escrowAt(from, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := escrow.CanApprove(who, now); err != nil {
+		return nil, err
+	}
+
+	tx := &types.EscrowApproveOperation{
+		From:     escrow.From,
+		To:       escrow.To,
+		Agent:    escrow.Agent,
+		Who:      who,
+		EscrowId: id,
+		Approve:  approve,
+	}
+	resp, err := api.Send_Trx(who, tx)
+	return resp, errors.Wrapf(err, "Error EscrowApprove: ")
+}
+
+// EscrowDispute opens a dispute as the sender or the receiver, after which only the agent can release the funds.
+func (api *Client) EscrowDispute(from string, id uint32, who string) (*BResp, error) {
+	escrow, now, err := api.escrowAt(from, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := escrow.CanDispute(who, now); err != nil {
+		return nil, err
+	}
+
+	tx := &types.EscrowDisputeOperation{
+		From:     escrow.From,
+		To:       escrow.To,
+		Agent:    escrow.Agent,
+		Who:      who,
+		EscrowId: id,
+	}
+	resp, err := api.Send_Trx(who, tx)
+	return resp, errors.Wrapf(err, "Error EscrowDispute: ")
+}
+
+// EscrowRelease releases the given amounts of the escrow to the receiver, which is either the sender or the receiver of the escrow.
+func (api *Client) EscrowRelease(from string, id uint32, who, receiver string, sbd, steem *types.Asset) (*BResp, error) {
+	escrow, now, err := api.escrowAt(from, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := escrow.CanRelease(who, receiver, sbd, steem, now); err != nil {
+		return nil, err
+	}
+
+	tx := &types.EscrowReleaseOperation{
+		From:        escrow.From,
+		To:          escrow.To,
+		Agent:       escrow.Agent,
+		Who:         who,
+		Receiver:    receiver,
+		EscrowId:    id,
+		SbdAmount:   sbd.String(),
+		SteemAmount: steem.String(),
+	}
+	resp, err := api.Send_Trx(who, tx)
+	return resp, errors.Wrapf(err, "Error EscrowRelease: ")
+}
+
+// escrowAt returns the escrow and the head block time its state is checked at.
+func (api *Client) escrowAt(from string, id uint32) (*Escrow, time.Time, error) {
+	now, err := api.headTime()
+	if err != nil {
+		return nil, now, err
+	}
+	escrow, err := api.GetEscrow(from, id)
+	return escrow, now, err
+}
+
+func (api *Client) headTime() (time.Time, error) {
+	props, err := api.Rpc.Database.GetDynamicGlobalProperties()
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "Error GetDynamicGlobalProperties: ")
+	}
+	if props.Time == nil || props.Time.Time == nil {
+		return time.Now().UTC(), nil
+	}
+	return *props.Time.Time, nil
+}
@@ -0,0 +1,142 @@
+package client
+
+import (
+	// Stdlib
+	"encoding/json"
+	"testing"
+	"time"
+
+	// Vendor
+	"github.com/pkg/errors"
+
+	// RPC
+	"github.com/asuleymanov/rpc/apis/database"
+	"github.com/asuleymanov/rpc/types"
+)
+
+func asset(t *testing.T, s string) *types.Asset {
+	a, err := types.ParseAsset(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestEscrow_Lifecycle(t *testing.T) {
+	var raw database.Escrow
+	if err := json.Unmarshal([]byte(`{
+		"escrow_id": 7,
+		"from": "alice",
+		"to": "bob",
+		"agent": "carol",
+		"ratification_deadline": "2018-06-01T00:00:00",
+		"escrow_expiration": "2018-06-10T00:00:00",
+		"sbd_balance": "10.000 SBD",
+		"steem_balance": "0.000 STEEM",
+		"pending_fee": "0.100 STEEM"
+	}`), &raw); err != nil {
+		t.Fatal(err)
+	}
+	escrow, err := NewEscrow(&raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if escrow.SBD.String() != "10.000 SBD" || escrow.PendingFee.String() != "0.100 STEEM" {
+		t.Fatalf("unexpected escrow: %+v", escrow)
+	}
+
+	before := time.Date(2018, 5, 31, 0, 0, 0, 0, time.UTC)
+	during := time.Date(2018, 6, 5, 0, 0, 0, 0, time.UTC)
+	after := time.Date(2018, 6, 11, 0, 0, 0, 0, time.UTC)
+	half, zero := asset(t, "5.000 SBD"), asset(t, "0.000 STEEM")
+
+	allowed := func(name string, err error) {
+		t.Helper()
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", name, err)
+		}
+	}
+	denied := func(name string, err error) {
+		t.Helper()
+		if errors.Cause(err) != ErrEscrowNotAllowed {
+			t.Errorf("%v: expected ErrEscrowNotAllowed, got %v", name, err)
+		}
+	}
+
+	// Pending: only the receiver and the agent can act.
+	if state := escrow.State(before); state != EscrowPending {
+		t.Errorf("expected pending, got %v", state)
+	}
+	allowed("bob approves", escrow.CanApprove("bob", before))
+	allowed("carol approves", escrow.CanApprove("carol", before))
+	denied("alice approves", escrow.CanApprove("alice", before))
+	denied("alice disputes pending", escrow.CanDispute("alice", before))
+	denied("alice releases pending", escrow.CanRelease("alice", "bob", half, zero, before))
+	if state := escrow.State(during); state != EscrowUnratified {
+		t.Errorf("expected unratified, got %v", state)
+	}
+	denied("bob approves late", escrow.CanApprove("bob", during))
+
+	// Ratified: the parties release to each other, the agent waits for a dispute.
+	escrow.ToApproved, escrow.AgentApproved = true, true
+	denied("bob approves twice", escrow.CanApprove("bob", before))
+	allowed("alice releases to bob", escrow.CanRelease("alice", "bob", half, zero, during))
+	allowed("bob releases to alice", escrow.CanRelease("bob", "alice", half, zero, during))
+	denied("alice releases to herself", escrow.CanRelease("alice", "alice", half, zero, during))
+	denied("carol releases undisputed", escrow.CanRelease("carol", "bob", half, zero, during))
+	denied("alice releases to dave", escrow.CanRelease("alice", "dave", half, zero, during))
+	if err := escrow.CanRelease("alice", "bob", asset(t, "11.000 SBD"), zero, during); err == nil {
+		t.Error("expected an error for releasing more than the balance")
+	}
+	allowed("bob disputes", escrow.CanDispute("bob", during))
+	denied("carol disputes", escrow.CanDispute("carol", during))
+
+	// Expired: either party releases to either.
+	if state := escrow.State(after); state != EscrowExpired {
+		t.Errorf("expected expired, got %v", state)
+	}
+	allowed("alice releases to herself after expiration", escrow.CanRelease("alice", "alice", half, zero, after))
+	denied("bob disputes after expiration", escrow.CanDispute("bob", after))
+
+	// Disputed: only the agent releases.
+	escrow.Disputed = true
+	allowed("carol releases disputed", escrow.CanRelease("carol", "alice", half, zero, during))
+	denied("bob releases disputed", escrow.CanRelease("bob", "alice", half, zero, during))
+	denied("alice disputes twice", escrow.CanDispute("alice", during))
+}
+
+func TestEscrowRequest_Validate(t *testing.T) {
+	now := time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)
+	valid := func() *EscrowRequest {
+		return &EscrowRequest{
+			From:                 "alice",
+			To:                   "bob",
+			Agent:                "carol",
+			SBD:                  asset(t, "10.000 SBD"),
+			Steem:                asset(t, "0.000 STEEM"),
+			Fee:                  asset(t, "0.100 STEEM"),
+			RatificationDeadline: now.Add(24 * time.Hour),
+			Expiration:           now.Add(7 * 24 * time.Hour),
+		}
+	}
+	if err := valid().Validate(now); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := []func(*EscrowRequest){
+		func(req *EscrowRequest) { req.Agent = "alice" },
+		func(req *EscrowRequest) { req.To = "" },
+		func(req *EscrowRequest) { req.Fee = asset(t, "1.000 VESTS") },
+		func(req *EscrowRequest) { req.SBD = asset(t, "0.000 SBD") },
+		func(req *EscrowRequest) { req.Steem = asset(t, "1.000 SBD") },
+		func(req *EscrowRequest) { req.RatificationDeadline = now },
+		func(req *EscrowRequest) { req.Expiration = req.RatificationDeadline },
+	}
+	for i, modify := range invalid {
+		req := valid()
+		modify(req)
+		if err := req.Validate(now); err == nil {
+			t.Errorf("%v: expected an error for %+v", i, req)
+		}
+	}
+}
@@ -17,6 +17,9 @@ import (
 	"github.com/asuleymanov/rpc/types"
 )
 
+// AppName is the app reported in the metadata of posts and comments.
+const AppName = "steem-go"
+
 func (api *Client) SteemPerMvest() (float64, error) {
 	dgp, errdgp := api.Rpc.Database.GetDynamicGlobalProperties()
 	if errdgp != nil {
@@ -48,7 +51,11 @@ func (api *Client) Vote(user_name, author_name, permlink string, weight int) err
 	if weight > 10000 {
 		weight = 10000
 	}
-	if api.Verify_Voter_Weight(author_name, permlink, user_name, weight) {
+	vote, err := api.GetVote(author_name, permlink, user_name)
+	if err != nil {
+		return errors.Wrapf(err, "Error Vote: ")
+	}
+	if vote != nil && vote.Percent == weight {
 		return errors.New("The voter is on the list")
 	}
 	tx := &types.VoteOperation{
@@ -67,88 +74,34 @@ func (api *Client) Vote(user_name, author_name, permlink string, weight int) err
 }
 
 func (api *Client) Comment(user_name, author_name, ppermlink, body string, v *PC_Vote, o *PC_Options) error {
-	var trx []types.Operation
-
-	times, _ := strconv.Unquote(time.Now().Add(30 * time.Second).UTC().Format(fdt))
-	permlink := "re-" + author_name + "-" + ppermlink + "-" + times
-	permlink = strings.Replace(permlink, ".", "-", -1)
-
-	tx := &types.CommentOperation{
+	result, err := api.Publish(&PublishRequest{
+		Author:         user_name,
 		ParentAuthor:   author_name,
 		ParentPermlink: ppermlink,
-		Author:         user_name,
-		Permlink:       permlink,
-		Title:          "",
 		Body:           body,
-		JsonMetadata:   "{\"app\":\"steem-go\"}",
-	}
-	trx = append(trx, tx)
-
-	if o != nil {
-		symbol := "SBD"
-		MAP := "1000000.000 " + symbol
-		PSD := o.Percent
-		if o.Percent == 0 {
-			MAP = "0.000 " + symbol
-			PSD = 10000
-		} else if o.Percent == 50 {
-			PSD = 10000
-		} else {
-			PSD = 0
-		}
-
-		var ext []interface{}
-		if len(o.BenefList) > 0 {
-			var ben_list []types.Beneficiarie
-			var benef types.CommentPayoutBeneficiaries
-			for _, val := range o.BenefList {
-				ben_list = append(ben_list, types.Beneficiarie{val.Account, val.Weight})
-			}
-			benef.Beneficiaries = ben_list
-			ext = append(ext, 0)
-			ext = append(ext, benef)
-		}
-		Extens := []interface{}{}
-		if len(ext) > 0 {
-			Extens = []interface{}{ext}
-		}
-
-		txo := &types.CommentOptionsOperation{
-			Author:               user_name,
-			Permlink:             permlink,
-			MaxAcceptedPayout:    MAP,
-			PercentSteemDollars:  PSD,
-			AllowVotes:           true,
-			AllowCurationRewards: true,
-			Extensions:           Extens,
-		}
-		trx = append(trx, txo)
-	}
-
-	if v != nil && v.Weight != 0 {
-		txv := &types.VoteOperation{
-			Voter:    user_name,
-			Author:   user_name,
-			Permlink: permlink,
-			Weight:   types.Int16(v.Weight),
-		}
-		trx = append(trx, txv)
-	}
-
-	resp, err := api.Send_Arr_Trx(user_name, trx)
+		Vote:           v,
+		Options:        o,
+	})
 	if err != nil {
 		return errors.Wrapf(err, "Error Comment : ")
-	} else {
-		log.Println("[Comment] Block -> ", resp.BlockNum, " User -> ", user_name)
-		return nil
 	}
+	log.Println("[Comment] Block -> ", result.BlockNum, " User -> ", user_name)
+	return nil
 }
 
 func (api *Client) DeleteComment(author_name, permlink string) error {
-	if api.Verify_Votes(author_name, permlink) {
+	voted, err := api.HasVotes(author_name, permlink)
+	if err != nil {
+		return errors.Wrapf(err, "Error Delete Comment: ")
+	}
+	if voted {
 		return errors.New("You can not delete already there are voted")
 	}
-	if api.Verify_Comments(author_name, permlink) {
+	commented, err := api.HasComments(author_name, permlink)
+	if err != nil {
+		return errors.Wrapf(err, "Error Delete Comment: ")
+	}
+	if commented {
 		return errors.New("You can not delete already have comments")
 	}
 	tx := &types.DeleteCommentOperation{
@@ -165,101 +118,43 @@ func (api *Client) DeleteComment(author_name, permlink string) error {
 }
 
 func (api *Client) Post(author_name, title, body, permlink, ptag, post_image string, tags []string, v *PC_Vote, o *PC_Options) error {
-	if permlink == "" {
-		permlink = translit.EncodeTitle(title)
-	} else {
+	if permlink != "" {
 		permlink = translit.EncodeTitle(permlink)
 	}
-	tag := translit.EncodeTags(tags)
-	if ptag == "" {
-		ptag = translit.EncodeTag(tags[0])
-	} else {
-		ptag = translit.EncodeTag(ptag)
-	}
-
-	json_meta := "{\"tags\":["
-	for k, v := range tag {
-		if k != len(tags)-1 {
-			json_meta = json_meta + "\"" + v + "\","
-		} else {
-			json_meta = json_meta + "\"" + v + "\"]"
-		}
-	}
-	if post_image != "" {
-		json_meta = json_meta + ",\"image\":[\"" + post_image + "\"]"
-	}
-	json_meta = json_meta + ",\"app\":\"steem-go\"}"
-
-	var trx []types.Operation
-	txp := &types.CommentOperation{
-		ParentAuthor:   "",
-		ParentPermlink: ptag,
+	req := &PublishRequest{
 		Author:         author_name,
-		Permlink:       permlink,
+		ParentPermlink: ptag,
 		Title:          title,
 		Body:           body,
-		JsonMetadata:   json_meta,
-	}
-	trx = append(trx, txp)
-
-	if o != nil {
-		symbol := "SBD"
-		MAP := "1000000.000 " + symbol
-		PSD := o.Percent
-		if o.Percent == 0 {
-			MAP = "0.000 " + symbol
-			PSD = 10000
-		} else if o.Percent == 50 {
-			PSD = 10000
-		} else {
-			PSD = 0
-		}
-
-		var ext []interface{}
-		if len(o.BenefList) > 0 {
-			var ben_list []types.Beneficiarie
-			var benef types.CommentPayoutBeneficiaries
-			for _, val := range o.BenefList {
-				ben_list = append(ben_list, types.Beneficiarie{val.Account, val.Weight})
-			}
-			benef.Beneficiaries = ben_list
-			ext = append(ext, 0)
-			ext = append(ext, benef)
-		}
-		Extens := []interface{}{}
-		if len(ext) > 0 {
-			Extens = []interface{}{ext}
-		}
-
-		txo := &types.CommentOptionsOperation{
-			Author:               author_name,
-			Permlink:             permlink,
-			MaxAcceptedPayout:    MAP,
-			PercentSteemDollars:  PSD,
-			AllowVotes:           true,
-			AllowCurationRewards: true,
-			Extensions:           Extens,
-		}
-		trx = append(trx, txo)
+		Permlink:       permlink,
+		Tags:           tags,
+		Image:          post_image,
+		Vote:           v,
+		Options:        o,
 	}
-
-	if v != nil && v.Weight != 0 {
-		txv := &types.VoteOperation{
-			Voter:    author_name,
-			Author:   author_name,
-			Permlink: permlink,
-			Weight:   types.Int16(v.Weight),
-		}
-		trx = append(trx, txv)
+	if err := api.editExisting(req); err != nil {
+		return errors.Wrapf(err, "Error Post : ")
 	}
-
-	resp, err := api.Send_Arr_Trx(author_name, trx)
+	result, err := api.Publish(req)
 	if err != nil {
 		return errors.Wrapf(err, "Error Post : ")
-	} else {
-		log.Println("[Post] Block -> ", resp.BlockNum, " User -> ", author_name)
+	}
+	log.Println("[Post] Block -> ", result.BlockNum, " User -> ", author_name)
+	return nil
+}
+
+// editExisting turns the request into an edit when its explicit permlink is already used,
+// Post has always updated the existing post then. The payout options cannot be changed and are dropped.
+func (api *Client) editExisting(req *PublishRequest) error {
+	if req.Permlink == "" {
 		return nil
 	}
+	exists, err := api.PostExists(req.Author, req.Permlink)
+	if err != nil || !exists {
+		return err
+	}
+	req.Edit, req.Options = true, nil
+	return nil
 }
 
 func (api *Client) Follow(follower, following string) error {
@@ -335,7 +230,11 @@ func (api *Client) Notice(follower, following string) error {
 }
 
 func (api *Client) Reblog(user_name, author_name, permlink string) error {
-	if api.Verify_Reblogs(author_name, permlink, user_name) {
+	reblogged, err := api.HasReblogged(author_name, permlink, user_name)
+	if err != nil {
+		return errors.Wrapf(err, "Error Reblog: ")
+	}
+	if reblogged {
 		return errors.New("The user already did repost")
 	}
 	json_string := "[\"reblog\",{\"account\":\"" + user_name + "\",\"author\":\"" + author_name + "\",\"permlink\":\"" + permlink + "\"}]"
@@ -661,81 +560,19 @@ func (api *Client) FeedPublish(publisher, base, quote string) error {
 }
 
 func (api *Client) Comment_Link(user_name, author_name, ppermlink, body string, v *PC_Vote, o *PC_Options) (string, error) {
-	var trx []types.Operation
-
-	times, _ := strconv.Unquote(time.Now().Add(30 * time.Second).UTC().Format(fdt))
-	permlink := "re-" + author_name + "-" + ppermlink + "-" + times
-	permlink = strings.Replace(permlink, ".", "-", -1)
-
-	tx := &types.CommentOperation{
+	result, err := api.Publish(&PublishRequest{
+		Author:         user_name,
 		ParentAuthor:   author_name,
 		ParentPermlink: ppermlink,
-		Author:         user_name,
-		Permlink:       permlink,
-		Title:          "",
 		Body:           body,
-		JsonMetadata:   "{\"app\":\"steem-go\"}",
-	}
-	trx = append(trx, tx)
-
-	if o != nil {
-		symbol := "SBD"
-		MAP := "1000000.000 " + symbol
-		PSD := o.Percent
-		if o.Percent == 0 {
-			MAP = "0.000 " + symbol
-			PSD = 10000
-		} else if o.Percent == 50 {
-			PSD = 10000
-		} else {
-			PSD = 0
-		}
-
-		var ext []interface{}
-		if len(o.BenefList) > 0 {
-			var ben_list []types.Beneficiarie
-			var benef types.CommentPayoutBeneficiaries
-			for _, val := range o.BenefList {
-				ben_list = append(ben_list, types.Beneficiarie{val.Account, val.Weight})
-			}
-			benef.Beneficiaries = ben_list
-			ext = append(ext, 0)
-			ext = append(ext, benef)
-		}
-		Extens := []interface{}{}
-		if len(ext) > 0 {
-			Extens = []interface{}{ext}
-		}
-
-		txo := &types.CommentOptionsOperation{
-			Author:               user_name,
-			Permlink:             permlink,
-			MaxAcceptedPayout:    MAP,
-			PercentSteemDollars:  PSD,
-			AllowVotes:           true,
-			AllowCurationRewards: true,
-			Extensions:           Extens,
-		}
-		trx = append(trx, txo)
-	}
-
-	if v != nil && v.Weight != 0 {
-		txv := &types.VoteOperation{
-			Voter:    user_name,
-			Author:   user_name,
-			Permlink: permlink,
-			Weight:   types.Int16(v.Weight),
-		}
-		trx = append(trx, txv)
-	}
-
-	resp, err := api.Send_Arr_Trx(user_name, trx)
+		Vote:           v,
+		Options:        o,
+	})
 	if err != nil {
 		return "", errors.Wrapf(err, "Error Comment : ")
-	} else {
-		log.Println("[Comment] Block -> ", resp.BlockNum, " User -> ", user_name)
-		return permlink, nil
 	}
+	log.Println("[Comment] Block -> ", result.BlockNum, " User -> ", user_name)
+	return result.Permlink, nil
 }
 
 func (api *Client) DelegateVestingShares(from, to, share string) error {
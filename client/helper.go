@@ -2,80 +2,148 @@ package client
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
+	"github.com/asuleymanov/rpc/apis/database"
+	"github.com/asuleymanov/rpc/iterator"
+	"github.com/asuleymanov/rpc/rewards"
 	"github.com/asuleymanov/rpc/types"
 )
 
 func (api *Client) Followers_List(username string) ([]string, error) {
 	var followers []string
-	fc, errfc := api.Rpc.Follow.GetFollowCount(username)
-	if errfc != nil {
-		return followers, errfc
-	} else {
-		fccount := fc.FollowerCount
-		i := 0
-		for i < fccount {
-			req, err := api.Rpc.Follow.GetFollowers(username, "", "blog", 1000)
-			if err != nil {
-				return followers, err
-			}
-
-			for _, v := range req {
-				followers = append(followers, v.Follower)
-			}
-			i = i + 1000
-		}
+	it := iterator.Followers(api.Rpc.Follow, username, "blog")
+	for it.Next() {
+		followers = append(followers, it.Value().Follower)
 	}
-
-	return followers, nil
+	return followers, it.Err()
 }
 
 func (api *Client) Following_List(username string) ([]string, error) {
 	var following []string
-	fc, errfc := api.Rpc.Follow.GetFollowCount(username)
-	if errfc != nil {
-		return following, errfc
-	} else {
-		fccount := fc.FollowingCount
-		i := 0
-		for i < fccount {
-			req, err := api.Rpc.Follow.GetFollowing(username, "", "blog", 100)
-			if err != nil {
-				return following, err
-			}
+	it := iterator.Following(api.Rpc.Follow, username, "blog")
+	for it.Next() {
+		following = append(following, it.Value().Following)
+	}
+	return following, it.Err()
+}
 
-			for _, v := range req {
-				following = append(following, v.Following)
-			}
-			i = i + 100
-		}
+// GetVotingPower returns the current voting power of the user in basis points (0-10000),
+// including the regeneration since the last vote at the head block time.
+func (api *Client) GetVotingPower(username string) (int, error) {
+	props, err := api.Rpc.Database.GetDynamicGlobalProperties()
+	if err != nil {
+		return 0, err
 	}
 
-	return following, nil
+	acc, err := api.getAccount(username)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	if props.Time != nil && props.Time.Time != nil {
+		now = *props.Time.Time
+	}
+	return rewards.VotingPower(acc, now), nil
 }
 
-func (api *Client) GetVotingPower(username string) (int, error) {
-	conf, errc := api.Rpc.Database.GetConfig()
-	if errc != nil {
-		return 0, errc
+// GetVoteValue estimates the rshares and the value of a vote of the user with the given weight.
+func (api *Client) GetVoteValue(username string, weight int) (*rewards.Vote, error) {
+	calc, err := rewards.Load(api.Rpc.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	acc, err := api.getAccount(username)
+	if err != nil {
+		return nil, err
+	}
+	return calc.Vote(acc, weight, time.Time{})
+}
+
+// GetPendingPayout estimates the pending payout of the post and its split
+// between the author, the curators and the beneficiaries.
+func (api *Client) GetPendingPayout(author, permlink string) (*rewards.Payout, error) {
+	calc, err := rewards.Load(api.Rpc.Database)
+	if err != nil {
+		return nil, err
 	}
 
-	acc, erra := api.Rpc.Database.GetAccounts([]string{username})
-	if erra != nil {
-		return 0, erra
+	content, err := api.Rpc.Database.GetContent(author, permlink)
+	if err != nil {
+		return nil, err
+	}
+	if content.Author == "" {
+		return nil, ErrContentNotFound
 	}
+	return calc.PendingPayout(content)
+}
 
-	vp := acc[0].VotingPower
-	lvt := acc[0].LastVoteTime
-	dtn := time.Now()
+// EstimateCuration estimates the curation reward of a vote of the voter on the post
+// cast now and after each of the given delays, e.g. to find the best time to vote.
+// The voting power regenerated until the vote is taken into account.
+func (api *Client) EstimateCuration(voter, author, permlink string, weight int, delays ...time.Duration) ([]*rewards.Curation, error) {
+	calc, err := rewards.Load(api.Rpc.Database)
+	if err != nil {
+		return nil, err
+	}
+	conf, err := api.Rpc.Database.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	if window := conf.SteemitReverseAuctionWindowSeconds; window != nil && window.Int != nil {
+		calc.ReverseAuctionWindow = time.Duration(window.Int64()) * time.Second
+	}
 
-	regen := conf.Steemit100Percent * int(dtn.Sub(*lvt.Time).Seconds()) / conf.SteemitVoteRegenerationSeconds
-	power := (vp + regen) // 100
-	if power > 10000 {
-		power = 10000
+	acc, err := api.getAccount(voter)
+	if err != nil {
+		return nil, err
+	}
+	content, err := api.Rpc.Database.GetContent(author, permlink)
+	if err != nil {
+		return nil, err
+	}
+	if content.Author == "" {
+		return nil, ErrContentNotFound
+	}
+	votes, err := api.Rpc.Database.GetActiveVotes(author, permlink)
+	if err != nil {
+		return nil, err
+	}
+	others := make([]*database.VoteState, 0, len(votes))
+	for _, vote := range votes {
+		if vote.Voter != voter {
+			others = append(others, vote)
+		}
+	}
+
+	estimates := make([]*rewards.Curation, 0, len(delays)+1)
+	for _, delay := range append([]time.Duration{0}, delays...) {
+		at := calc.Now.Add(delay)
+		vote, err := calc.Vote(acc, weight, at)
+		if err != nil {
+			return nil, err
+		}
+		curation, err := calc.Curation(content, others, vote.Rshares, at)
+		if err != nil {
+			return nil, err
+		}
+		estimates = append(estimates, curation)
+	}
+	return estimates, nil
+}
+
+func (api *Client) getAccount(username string) (*database.Account, error) {
+	accounts, err := api.Rpc.Database.GetAccounts([]string{username})
+	if err != nil {
+		return nil, err
+	}
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("account %v not found", username)
 	}
-	return power, nil
+	return accounts[0], nil
 }
 
 func (api *Client) GetAuthorReward(username, permlink string, full bool) (*types.AuthorRewardOperation, error) {
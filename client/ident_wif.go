@@ -73,10 +73,19 @@ func init() {
 
 }
 
+// operationKeys returns the keys the operation is signed with,
+// an account update changing the owner authority needs the owner key.
+func operationKeys(op types.Operation) []string {
+	if update, ok := op.(*types.AccountUpdateOperation); ok && update.Owner != nil {
+		return []string{RoleOwner}
+	}
+	return OpTypeKey[op.Type()]
+}
+
 func (api *Client) Signing_Keys(username string, trx types.Operation) [][]byte {
 	var keys [][]byte
 	if _, ok := Key_List[username]; ok {
-		op_keys := OpTypeKey[trx.Type()]
+		op_keys := operationKeys(trx)
 		for _, val := range op_keys {
 			switch {
 			case val == "posting":
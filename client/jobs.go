@@ -0,0 +1,419 @@
+package client
+
+import (
+	// Stdlib
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	// Vendor
+	"github.com/pkg/errors"
+
+	// RPC
+	"github.com/asuleymanov/rpc/types"
+)
+
+var (
+	// ErrJobSchedulerClosed is returned when scheduling jobs on a closed scheduler.
+	ErrJobSchedulerClosed = errors.New("job scheduler closed")
+	// ErrJobNotFound is returned when cancelling a job that does not exist.
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobSkipped is reported when the preconditions of a job no longer hold at execution time.
+	ErrJobSkipped = errors.New("job skipped")
+	// ErrJobInterrupted is reported for a job that was being broadcast when the scheduler stopped,
+	// it may or may not have been broadcast and is not executed again.
+	ErrJobInterrupted = errors.New("job interrupted")
+)
+
+// Job is a broadcast scheduled for a future time, exactly one of Comment and Vote is set.
+type Job struct {
+	ID      string                  `json:"id"`
+	At      time.Time               `json:"at"`
+	Comment *types.CommentOperation `json:"comment,omitempty"`
+	Vote    *types.VoteOperation    `json:"vote,omitempty"`
+
+	// Running is set in the file before the job is broadcast.
+	Running bool `json:"running,omitempty"`
+}
+
+// validate checks that the job holds exactly one operation.
+func (job *Job) validate() error {
+	if (job.Comment == nil) == (job.Vote == nil) {
+		return errors.New("a job must contain either a comment or a vote")
+	}
+	return nil
+}
+
+// clone returns a copy of the job and its operation,
+// the scheduler keeps modifying its own while the job runs.
+func (job *Job) clone() *Job {
+	clone := *job
+	if job.Comment != nil {
+		op := *job.Comment
+		clone.Comment = &op
+	}
+	if job.Vote != nil {
+		op := *job.Vote
+		clone.Vote = &op
+	}
+	return &clone
+}
+
+// username returns the account signing the job.
+func (job *Job) username() string {
+	if job.Comment != nil {
+		return job.Comment.Author
+	}
+	return job.Vote.Voter
+}
+
+// JobResult is the outcome of a job. Err wraps ErrJobSkipped when the preconditions
+// did not hold, use errors.Cause to check for it.
+type JobResult struct {
+	Job  *Job
+	Resp *BResp
+	Err  error
+}
+
+// JobScheduler broadcasts comments and votes at a given time.
+//
+// Pending jobs are stored in a JSON file, so they survive restarts. A job is marked
+// as running in the file before it is broadcast, a job still marked when the file is loaded
+// is not executed again but reported with ErrJobInterrupted, so a crash never repeats a broadcast.
+// The preconditions are checked again at execution time: comments are skipped
+// when the permlink is already used or the parent no longer exists, votes are
+// skipped when the post no longer exists or the voter already voted.
+type JobScheduler struct {
+	api       *Client
+	path      string
+	broadcast func(username string, op types.Operation) (*BResp, error)
+
+	mu     sync.Mutex
+	jobs   map[string]*Job
+	closed bool
+
+	results chan JobResult
+	wake    chan struct{}
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewJobScheduler creates a scheduler storing the jobs in the file at path.
+// Jobs stored by a previous scheduler are loaded, the ones that are due are executed immediately.
+func (api *Client) NewJobScheduler(path string) (*JobScheduler, error) {
+	s := &JobScheduler{
+		api:       api,
+		path:      path,
+		broadcast: api.Send_Trx,
+		jobs:      make(map[string]*Job),
+		results:   make(chan JobResult, 64),
+		wake:      make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	s.wg.Add(1)
+	go s.loop()
+	return s, nil
+}
+
+// Results returns the channel receiving the outcome of every executed job.
+// It must be drained, the scheduler blocks when it is full.
+func (s *JobScheduler) Results() <-chan JobResult {
+	return s.results
+}
+
+// ScheduleComment schedules a comment operation, e.g. a post, to be broadcast at the given time.
+func (s *JobScheduler) ScheduleComment(at time.Time, op *types.CommentOperation) (*Job, error) {
+	return s.schedule(&Job{At: at, Comment: op})
+}
+
+// ScheduleVote schedules a vote to be broadcast at the given time.
+func (s *JobScheduler) ScheduleVote(at time.Time, op *types.VoteOperation) (*Job, error) {
+	return s.schedule(&Job{At: at, Vote: op})
+}
+
+// ScheduleVoteAtAge schedules a vote to be broadcast when the post reaches the given age.
+func (s *JobScheduler) ScheduleVoteAtAge(op *types.VoteOperation, age time.Duration) (*Job, error) {
+	content, err := s.api.Rpc.Database.GetContent(op.Author, op.Permlink)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error GetContent: ")
+	}
+	if content.Author == "" || content.Created == nil || content.Created.Time == nil {
+		return nil, errors.Wrapf(ErrContentNotFound, "%v/%v", op.Author, op.Permlink)
+	}
+	return s.ScheduleVote(content.Created.Add(age), op)
+}
+
+// Cancel removes a pending job.
+func (s *JobScheduler) Cancel(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[id]; !ok {
+		return errors.Wrap(ErrJobNotFound, id)
+	}
+	delete(s.jobs, id)
+	s.notify()
+	return s.save()
+}
+
+// Jobs returns copies of the pending jobs ordered by time.
+func (s *JobScheduler) Jobs() []*Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs := s.pending()
+	for i, job := range jobs {
+		jobs[i] = job.clone()
+	}
+	return jobs
+}
+
+// Close stops the scheduler, pending jobs stay in the file.
+func (s *JobScheduler) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.done)
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return nil
+}
+
+func (s *JobScheduler) schedule(job *Job) (*Job, error) {
+	if err := job.validate(); err != nil {
+		return nil, err
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, errors.Wrap(err, "failed to generate job ID")
+	}
+	job.ID = hex.EncodeToString(id)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, ErrJobSchedulerClosed
+	}
+	// The caller keeps the operation, the scheduler works on its own copy.
+	s.jobs[job.ID] = job.clone()
+	if err := s.save(); err != nil {
+		delete(s.jobs, job.ID)
+		return nil, err
+	}
+	s.notify()
+	return job, nil
+}
+
+func (s *JobScheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *JobScheduler) loop() {
+	defer s.wg.Done()
+	for {
+		s.mu.Lock()
+		pending := s.pending()
+		s.mu.Unlock()
+
+		if len(pending) == 0 {
+			select {
+			case <-s.wake:
+				continue
+			case <-s.done:
+				return
+			}
+		}
+
+		next := pending[0]
+		if delay := time.Until(next.At); delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-s.wake:
+				// The jobs changed, start over.
+				timer.Stop()
+				continue
+			case <-s.done:
+				timer.Stop()
+				return
+			}
+		}
+		s.run(next)
+	}
+}
+
+// run executes the job unless it was cancelled in the meantime.
+func (s *JobScheduler) run(job *Job) {
+	s.mu.Lock()
+	if _, ok := s.jobs[job.ID]; !ok {
+		s.mu.Unlock()
+		return
+	}
+	interrupted := job.Running
+	job.Running = true
+	err := s.save()
+	s.mu.Unlock()
+
+	result := JobResult{Job: job}
+	switch {
+	case interrupted:
+		result.Err = errors.Wrap(ErrJobInterrupted, job.ID)
+	case err != nil:
+		// Without the running mark a crash could broadcast the job twice.
+		result.Err = err
+	default:
+		result.Resp, result.Err = s.execute(job)
+	}
+
+	s.mu.Lock()
+	delete(s.jobs, job.ID)
+	if err := s.save(); err != nil && result.Err == nil {
+		result.Err = err
+	}
+	s.mu.Unlock()
+
+	select {
+	case s.results <- result:
+	case <-s.done:
+	}
+}
+
+// execute broadcasts the job when its preconditions hold.
+func (s *JobScheduler) execute(job *Job) (*BResp, error) {
+	if err := s.check(job); err != nil {
+		return nil, err
+	}
+	var op types.Operation = job.Vote
+	if job.Comment != nil {
+		op = job.Comment
+	}
+	return s.broadcast(job.username(), op)
+}
+
+// check verifies the preconditions of the job.
+func (s *JobScheduler) check(job *Job) error {
+	db := s.api.Rpc.Database
+	if op := job.Comment; op != nil {
+		existing, err := db.GetContent(op.Author, op.Permlink)
+		if err != nil {
+			return errors.Wrapf(err, "Error GetContent: ")
+		}
+		if existing.Author != "" {
+			return errors.Wrapf(ErrJobSkipped, "%v/%v already exists", op.Author, op.Permlink)
+		}
+		if op.ParentAuthor != "" {
+			parent, err := db.GetContent(op.ParentAuthor, op.ParentPermlink)
+			if err != nil {
+				return errors.Wrapf(err, "Error GetContent: ")
+			}
+			if parent.Author == "" {
+				return errors.Wrapf(ErrJobSkipped, "parent %v/%v does not exist", op.ParentAuthor, op.ParentPermlink)
+			}
+		}
+		return nil
+	}
+
+	op := job.Vote
+	post, err := db.GetContent(op.Author, op.Permlink)
+	if err != nil {
+		return errors.Wrapf(err, "Error GetContent: ")
+	}
+	if post.Author == "" {
+		return errors.Wrapf(ErrJobSkipped, "%v/%v does not exist", op.Author, op.Permlink)
+	}
+	voted, err := s.api.HasVoted(op.Author, op.Permlink, op.Voter)
+	if err != nil {
+		return err
+	}
+	if voted {
+		return errors.Wrapf(ErrJobSkipped, "%v already voted for %v/%v", op.Voter, op.Author, op.Permlink)
+	}
+	return nil
+}
+
+func (s *JobScheduler) pending() []*Job {
+	jobs := make([]*Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].At.Equal(jobs[j].At) {
+			return jobs[i].At.Before(jobs[j].At)
+		}
+		return jobs[i].ID < jobs[j].ID
+	})
+	return jobs
+}
+
+func (s *JobScheduler) load() error {
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to read jobs from %v", s.path)
+	}
+
+	// Unknown fields are rejected, they would be operations this version cannot execute.
+	var jobs []*Job
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&jobs); err != nil {
+		return errors.Wrapf(err, "failed to parse jobs from %v", s.path)
+	}
+	for _, job := range jobs {
+		if job == nil || job.ID == "" {
+			return errors.Errorf("failed to parse jobs from %v: a job without ID", s.path)
+		}
+		if err := job.validate(); err != nil {
+			return errors.Wrapf(err, "failed to parse job %v from %v", job.ID, s.path)
+		}
+		s.jobs[job.ID] = job
+	}
+	return nil
+}
+
+// save writes the pending jobs, the file is replaced atomically.
+func (s *JobScheduler) save() error {
+	data, err := json.MarshalIndent(s.pending(), "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to encode jobs")
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return errors.Wrapf(err, "failed to save jobs to %v", s.path)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return errors.Wrapf(err, "failed to save jobs to %v", s.path)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return errors.Wrapf(err, "failed to save jobs to %v", s.path)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		os.Remove(tmp.Name())
+		return errors.Wrapf(err, "failed to save jobs to %v", s.path)
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package client
+
+import (
+	// Stdlib
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	// Vendor
+	"github.com/pkg/errors"
+
+	// RPC
+	"github.com/asuleymanov/rpc"
+	"github.com/asuleymanov/rpc/types"
+)
+
+func TestJobScheduler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jobs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "jobs.json")
+
+	thread := &fakeThread{
+		posts:  map[string]post{"alice/post": {created: "2018-01-01T00:00:00"}},
+		voters: map[string][]string{"alice/post": {"carol"}},
+	}
+	client, err := rpc.NewClient(thread)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := &Client{Rpc: client}
+
+	s, err := api.NewJobScheduler(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var broadcast []string
+	s.broadcast = func(username string, op types.Operation) (*BResp, error) {
+		// The job is marked as running in the file before the broadcast.
+		data, err := ioutil.ReadFile(path)
+		if err != nil || !strings.Contains(string(data), `"running": true`) {
+			t.Errorf("expected a running job in %s, %v", data, err)
+		}
+		broadcast = append(broadcast, username)
+		return &BResp{BlockNum: 1}, nil
+	}
+
+	// A future post survives a restart.
+	post := &types.CommentOperation{ParentPermlink: "golang", Author: "alice", Permlink: "later", Body: "Later"}
+	job, err := s.ScheduleComment(time.Now().Add(time.Hour), post)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.ScheduleVote(time.Now(), &types.VoteOperation{Voter: "bob", Author: "alice", Permlink: "post", Weight: 10000}); err != nil {
+		t.Fatal(err)
+	}
+	if result := <-s.Results(); result.Err != nil || result.Resp == nil {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	if _, err := s.ScheduleVote(time.Now(), &types.VoteOperation{Voter: "carol", Author: "alice", Permlink: "post", Weight: 10000}); err != nil {
+		t.Fatal(err)
+	}
+	if result := <-s.Results(); errors.Cause(result.Err) != ErrJobSkipped {
+		t.Errorf("expected ErrJobSkipped, got %v", result.Err)
+	}
+	if len(broadcast) != 1 || broadcast[0] != "bob" {
+		t.Errorf("expected a single broadcast by bob, got %v", broadcast)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = api.NewJobScheduler(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	jobs := s.Jobs()
+	if len(jobs) != 1 || jobs[0].ID != job.ID || jobs[0].Comment.Permlink != "later" {
+		t.Fatalf("expected the post to be loaded, got %+v", jobs)
+	}
+	if err := s.Cancel(job.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Cancel(job.ID); errors.Cause(err) != ErrJobNotFound {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+	if len(s.Jobs()) != 0 {
+		t.Error("expected no pending jobs")
+	}
+}
+
+func TestJobScheduler_Jobs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jobs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	client, err := rpc.NewClient(&fakeThread{posts: map[string]post{"alice/post": {created: "2018-01-01T00:00:00"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := &Client{Rpc: client}
+	s, err := api.NewJobScheduler(filepath.Join(dir, "jobs.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	broadcasting, release := make(chan struct{}), make(chan struct{})
+	s.broadcast = func(username string, op types.Operation) (*BResp, error) {
+		close(broadcasting)
+		<-release
+		return &BResp{BlockNum: 1}, nil
+	}
+
+	// Changing the scheduled operation or the returned jobs does not affect the scheduler.
+	post := &types.CommentOperation{ParentPermlink: "golang", Author: "alice", Permlink: "later", Body: "Later"}
+	later, err := s.ScheduleComment(time.Now().Add(time.Hour), post)
+	if err != nil {
+		t.Fatal(err)
+	}
+	post.Permlink = "changed"
+	later.At = time.Now()
+	jobs := s.Jobs()
+	jobs[0].Comment.Body = "Changed"
+	if jobs = s.Jobs(); len(jobs) != 1 || jobs[0].Comment.Permlink != "later" || jobs[0].Comment.Body != "Later" ||
+		!jobs[0].At.After(time.Now()) {
+		t.Fatalf("unexpected jobs %+v", jobs)
+	}
+
+	// The running job can be listed while it is broadcast.
+	vote, err := s.ScheduleVote(time.Now(), &types.VoteOperation{Voter: "bob", Author: "alice", Permlink: "post", Weight: 10000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-broadcasting
+	var running *Job
+	for _, job := range s.Jobs() {
+		if job.ID == vote.ID {
+			running = job
+		}
+	}
+	if running == nil || !running.Running {
+		t.Errorf("expected the vote to be running, got %+v", running)
+	}
+	if vote.Running {
+		t.Error("the returned job was changed by the scheduler")
+	}
+	close(release)
+	if result := <-s.Results(); result.Err != nil {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestJobScheduler_Load(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jobs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "jobs.json")
+
+	client, err := rpc.NewClient(&fakeThread{posts: map[string]post{"alice/post": {created: "2018-01-01T00:00:00"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := &Client{Rpc: client}
+
+	for name, data := range map[string]string{
+		"no operation":    `[{"id":"a","at":"2018-01-01T00:00:00Z"}]`,
+		"two operations":  `[{"id":"a","at":"2018-01-01T00:00:00Z","vote":{"voter":"bob"},"comment":{"author":"bob"}}]`,
+		"unknown kind":    `[{"id":"a","at":"2018-01-01T00:00:00Z","transfer":{"from":"bob"}}]`,
+		"missing ID":      `[{"at":"2018-01-01T00:00:00Z","vote":{"voter":"bob"}}]`,
+		"null job":        `[null]`,
+		"invalid content": `{`,
+	} {
+		if err := ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if s, err := api.NewJobScheduler(path); err == nil {
+			s.Close()
+			t.Errorf("%v: expected an error", name)
+		}
+	}
+
+	// A job interrupted during the broadcast is reported, not executed again.
+	data := `[{"id":"a","at":"2018-01-01T00:00:00Z","vote":{"voter":"bob","author":"alice","permlink":"post","weight":10000},"running":true}]`
+	if err := ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	s, err := api.NewJobScheduler(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	s.broadcast = func(username string, op types.Operation) (*BResp, error) {
+		t.Error("unexpected broadcast of an interrupted job")
+		return &BResp{}, nil
+	}
+	if result := <-s.Results(); errors.Cause(result.Err) != ErrJobInterrupted || result.Job.ID != "a" {
+		t.Errorf("expected ErrJobInterrupted, got %+v", result)
+	}
+	if len(s.Jobs()) != 0 {
+		t.Error("expected no pending jobs")
+	}
+}
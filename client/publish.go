@@ -0,0 +1,267 @@
+package client
+
+import (
+	// Stdlib
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	// Vendor
+	"github.com/pkg/errors"
+
+	// RPC
+	"github.com/asuleymanov/rpc/content"
+	"github.com/asuleymanov/rpc/translit"
+	"github.com/asuleymanov/rpc/types"
+)
+
+// MaxPermlinkLength is the maximum permlink length accepted by steemd.
+const MaxPermlinkLength = 255
+
+// ErrPermlinkExists is returned by Publish for new content under an explicit permlink that is already used,
+// Post edits the existing post instead.
+var ErrPermlinkExists = errors.New("permlink already exists")
+
+var permlinkPattern = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+// PublishRequest describes a post or a reply to publish or to edit.
+type PublishRequest struct {
+	// Author is the account publishing the content.
+	Author string
+
+	// ParentAuthor and ParentPermlink identify the content replied to.
+	// Leave ParentAuthor empty to publish a post, ParentPermlink is then the category,
+	// the first tag being used when left empty.
+	ParentAuthor   string
+	ParentPermlink string
+
+	Title string
+	Body  string
+
+	// Permlink is generated from the title, or from the parent for replies, when left empty.
+	// A generated permlink is made unique, an explicit one must not exist yet unless Edit is set.
+	Permlink string
+
+	// Tags are transliterated when needed.
+	Tags []string
+	// Image is the thumbnail image, images found in the body are added to the metadata as well.
+	Image string
+	// Metadata is used instead of the generated metadata when set.
+	Metadata *types.PostMetadata
+
+	// Edit updates the existing content with the given Permlink. The parent cannot be changed,
+	// the title and the tags are kept unless given. The body is sent as a patch when it is shorter.
+	Edit bool
+
+	// Vote is an optional self vote.
+	Vote *PC_Vote
+	// Options are optional payout options, not allowed when editing.
+	Options *PC_Options
+}
+
+// PublishResult is the result of Publish.
+type PublishResult struct {
+	Author   string
+	Permlink string
+	// Edited is set when existing content was updated.
+	Edited bool
+	*BResp
+}
+
+// Publish broadcasts a new post or reply, or updates an existing one.
+// Unlike Post and Comment, it does not log anything.
+func (api *Client) Publish(req *PublishRequest) (*PublishResult, error) {
+	ops, result, err := api.publishOperations(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := api.Send_Arr_Trx(req.Author, ops)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error Publish: ")
+	}
+	result.BResp = resp
+	return result, nil
+}
+
+// Edit replaces the body of existing content, sending only a patch when it is shorter.
+func (api *Client) Edit(author, permlink, body string) (*PublishResult, error) {
+	return api.Publish(&PublishRequest{
+		Author:   author,
+		Permlink: permlink,
+		Body:     body,
+		Edit:     true,
+	})
+}
+
+// publishOperations returns the operations broadcast by Publish.
+func (api *Client) publishOperations(req *PublishRequest) ([]types.Operation, *PublishResult, error) {
+	if req.Author == "" {
+		return nil, nil, errors.New("publish: author is required")
+	}
+	if strings.TrimSpace(req.Body) == "" {
+		return nil, nil, errors.New("publish: body is required")
+	}
+
+	tags := translit.EncodeTags(req.Tags)
+	op := &types.CommentOperation{
+		ParentAuthor:   req.ParentAuthor,
+		ParentPermlink: req.ParentPermlink,
+		Author:         req.Author,
+		Title:          req.Title,
+		Body:           req.Body,
+	}
+
+	if req.Edit {
+		if req.Permlink == "" {
+			return nil, nil, errors.New("publish: permlink is required to edit")
+		}
+		if req.Options != nil {
+			return nil, nil, errors.New("publish: payout options cannot be changed when editing")
+		}
+		existing, err := api.Rpc.Database.GetContent(req.Author, req.Permlink)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "Error GetContent: ")
+		}
+		if existing.Author == "" {
+			return nil, nil, errors.Wrapf(ErrContentNotFound, "%v/%v", req.Author, req.Permlink)
+		}
+		op.Permlink = existing.Permlink
+		op.ParentAuthor, op.ParentPermlink = existing.ParentAuthor, existing.ParentPermlink
+		if op.Title == "" {
+			op.Title = existing.Title
+		}
+		// Long bodies are sent as a patch when it is shorter.
+		op.Body = content.EditBody(existing.Body, req.Body)
+		if len(tags) == 0 && existing.JsonMetadata != nil && existing.JsonMetadata.Post != nil {
+			tags = existing.JsonMetadata.Post.Tags
+		}
+	} else {
+		if op.ParentAuthor == "" && op.ParentPermlink == "" {
+			if len(tags) == 0 {
+				return nil, nil, errors.New("publish: a category or at least one tag is required for posts")
+			}
+			op.ParentPermlink = tags[0]
+		}
+		if op.ParentAuthor == "" {
+			op.ParentPermlink = translit.EncodeTag(op.ParentPermlink)
+		}
+
+		permlink, err := api.newPermlink(req, op)
+		if err != nil {
+			return nil, nil, err
+		}
+		op.Permlink = permlink
+	}
+
+	metadata := req.Metadata
+	if metadata == nil {
+		metadata = newPostMetadata(req.Body, req.Image, tags)
+	}
+	jsonMeta, err := metadata.Encode()
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "publish: invalid metadata")
+	}
+	op.JsonMetadata = jsonMeta
+
+	ops := []types.Operation{op}
+	if req.Options != nil {
+		txo, err := api.commentOptions(req.Author, op.Permlink, req.Options)
+		if err != nil {
+			return nil, nil, err
+		}
+		ops = append(ops, txo)
+	}
+	if req.Vote != nil && req.Vote.Weight != 0 {
+		ops = append(ops, &types.VoteOperation{
+			Voter:    req.Author,
+			Author:   req.Author,
+			Permlink: op.Permlink,
+			Weight:   types.Int16(req.Vote.Weight),
+		})
+	}
+
+	return ops, &PublishResult{Author: req.Author, Permlink: op.Permlink, Edited: req.Edit}, nil
+}
+
+// newPermlink returns the permlink of new content. Generated permlinks get a timestamp
+// suffix when already used, explicit ones fail with ErrPermlinkExists.
+func (api *Client) newPermlink(req *PublishRequest, op *types.CommentOperation) (string, error) {
+	permlink := req.Permlink
+	generated := permlink == ""
+	if generated {
+		if op.ParentAuthor == "" && strings.TrimSpace(req.Title) != "" {
+			permlink = sanitizePermlink(translit.EncodeTitle(req.Title))
+		}
+		if permlink == "" {
+			permlink = sanitizePermlink("re-" + op.ParentAuthor + "-" + op.ParentPermlink + "-" + permlinkTime())
+		}
+	}
+	if len(permlink) > MaxPermlinkLength || !permlinkPattern.MatchString(permlink) {
+		return "", errors.Errorf("publish: invalid permlink %q", permlink)
+	}
+
+	exists, err := api.PostExists(req.Author, permlink)
+	if err != nil || !exists {
+		return permlink, err
+	}
+	if !generated {
+		return "", errors.Wrapf(ErrPermlinkExists, "%v/%v", req.Author, permlink)
+	}
+
+	suffix := "-" + permlinkTime()
+	if len(permlink)+len(suffix) > MaxPermlinkLength {
+		permlink = permlink[:MaxPermlinkLength-len(suffix)]
+	}
+	permlink += suffix
+	if exists, err = api.PostExists(req.Author, permlink); err != nil {
+		return "", err
+	}
+	if exists {
+		return "", errors.Wrapf(ErrPermlinkExists, "%v/%v", req.Author, permlink)
+	}
+	return permlink, nil
+}
+
+func permlinkTime() string {
+	times, _ := strconv.Unquote(time.Now().UTC().Format(fdt))
+	return times
+}
+
+func sanitizePermlink(permlink string) string {
+	permlink = strings.ToLower(permlink)
+	permlink = strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			return r
+		}
+		return '-'
+	}, permlink)
+	permlink = strings.Trim(permlink, "-")
+	if len(permlink) > MaxPermlinkLength {
+		permlink = strings.TrimRight(permlink[:MaxPermlinkLength], "-")
+	}
+	return permlink
+}
+
+// newPostMetadata builds the metadata from the body, the thumbnail image going first.
+func newPostMetadata(body, image string, tags []string) *types.PostMetadata {
+	analysis := content.Analyze(body)
+	metadata := &types.PostMetadata{
+		Tags:   tags,
+		App:    AppName,
+		Format: analysis.Format,
+		Image:  analysis.Images,
+		Links:  analysis.Links,
+		Users:  analysis.Users,
+	}
+	if image != "" {
+		metadata.Image = []string{image}
+		for _, img := range analysis.Images {
+			if img != image {
+				metadata.Image = append(metadata.Image, img)
+			}
+		}
+	}
+	return metadata
+}
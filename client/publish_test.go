@@ -0,0 +1,115 @@
+package client
+
+import (
+	// Stdlib
+	"strings"
+	"testing"
+
+	// Vendor
+	"github.com/pkg/errors"
+
+	// RPC
+	"github.com/asuleymanov/rpc"
+	"github.com/asuleymanov/rpc/content"
+	"github.com/asuleymanov/rpc/types"
+)
+
+var long = strings.Repeat("All work and no play makes Jack a dull boy. ", 40)
+
+func TestPublishOperations(t *testing.T) {
+	thread := &fakeThread{
+		posts: map[string]post{
+			"alice/hello-world": {created: "2018-01-01T00:00:00"},
+			"bob/reply":         {parent: "alice/hello-world", created: "2018-01-01T00:01:00", body: long},
+		},
+	}
+	client, err := rpc.NewClient(thread)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := &Client{Rpc: client}
+
+	// The generated permlink is taken, a suffix is added.
+	ops, result, err := api.publishOperations(&PublishRequest{
+		Author: "alice",
+		Title:  "Hello World",
+		Body:   "Hi @bob",
+		Tags:   []string{"Golang"},
+		Vote:   &PC_Vote{Weight: 10000},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(result.Permlink, "hello-world-") || result.Edited {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if len(ops) != 2 {
+		t.Fatalf("expected a comment and a vote, got %v operations", len(ops))
+	}
+	comment := ops[0].(*types.CommentOperation)
+	if comment.ParentPermlink != "golang" || comment.Permlink != result.Permlink {
+		t.Errorf("unexpected comment %+v", comment)
+	}
+	if expected := `{"app":"steem-go","format":"markdown","tags":["golang"],"users":["bob"]}`; comment.JsonMetadata != expected {
+		t.Errorf("expected metadata %v, got %v", expected, comment.JsonMetadata)
+	}
+
+	// An explicit permlink must not be taken.
+	_, _, err = api.publishOperations(&PublishRequest{Author: "alice", Permlink: "hello-world", Body: "x", Tags: []string{"golang"}})
+	if errors.Cause(err) != ErrPermlinkExists {
+		t.Errorf("expected ErrPermlinkExists, got %v", err)
+	}
+
+	// Post still edits the post with an existing explicit permlink.
+	req := &PublishRequest{
+		Author:   "alice",
+		Permlink: "hello-world",
+		Title:    "Hello again",
+		Body:     "x",
+		Tags:     []string{"golang"},
+		Options:  &PC_Options{Percent: PayoutPowerUp},
+	}
+	if err := api.editExisting(req); err != nil {
+		t.Fatal(err)
+	}
+	ops, result, err = api.publishOperations(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if comment := ops[0].(*types.CommentOperation); !result.Edited || comment.Permlink != "hello-world" || comment.Title != "Hello again" {
+		t.Errorf("unexpected edit %+v %+v", result, comment)
+	}
+	req = &PublishRequest{Author: "alice", Permlink: "new-post", Body: "x", Tags: []string{"golang"}}
+	if err := api.editExisting(req); err != nil || req.Edit {
+		t.Errorf("unexpected edit of a new post: %v", err)
+	}
+
+	// Editing keeps the parent, a small change is sent as a patch.
+	edited := strings.Replace(long, "Jack", "Jill", 1)
+	ops, result, err = api.publishOperations(&PublishRequest{Author: "bob", Permlink: "reply", Body: edited, Edit: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	comment = ops[0].(*types.CommentOperation)
+	if !result.Edited || comment.ParentAuthor != "alice" || comment.ParentPermlink != "hello-world" {
+		t.Errorf("unexpected edit %+v %+v", result, comment)
+	}
+	if len(comment.Body) >= len(edited) || content.ApplyPatch(long, comment.Body) != edited {
+		t.Errorf("expected a patch, got %q", comment.Body)
+	}
+
+	_, _, err = api.publishOperations(&PublishRequest{Author: "bob", Permlink: "missing", Body: "x", Edit: true})
+	if errors.Cause(err) != ErrContentNotFound {
+		t.Errorf("expected ErrContentNotFound, got %v", err)
+	}
+	_, _, err = api.publishOperations(&PublishRequest{
+		Author:   "bob",
+		Permlink: "reply",
+		Body:     "x",
+		Edit:     true,
+		Options:  &PC_Options{Percent: PayoutPowerUp},
+	})
+	if err == nil {
+		t.Error("expected payout options to be rejected when editing")
+	}
+}
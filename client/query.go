@@ -0,0 +1,121 @@
+package client
+
+import (
+	// Stdlib
+	"time"
+
+	// Vendor
+	"github.com/pkg/errors"
+
+	// RPC
+	"github.com/asuleymanov/rpc/apis/database"
+)
+
+// The functions below answer the questions the Verify_* functions answer,
+// but report the RPC errors instead of treating them as "no".
+
+// GetVote returns the vote of the voter on the post, or nil if the voter has not voted.
+// The vote holds the weight, the rshares and the time of the vote.
+func (api *Client) GetVote(author, permlink, voter string) (*database.VoteState, error) {
+	votes, err := api.Rpc.Database.GetActiveVotes(author, permlink)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error GetActiveVotes: ")
+	}
+	for _, v := range votes {
+		if v.Voter == voter {
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+// HasVoted reports whether the voter has voted on the post.
+func (api *Client) HasVoted(author, permlink, voter string) (bool, error) {
+	vote, err := api.GetVote(author, permlink, voter)
+	return vote != nil, err
+}
+
+// HasVotes reports whether anybody has voted on the post.
+func (api *Client) HasVotes(author, permlink string) (bool, error) {
+	votes, err := api.Rpc.Database.GetActiveVotes(author, permlink)
+	if err != nil {
+		return false, errors.Wrapf(err, "Error GetActiveVotes: ")
+	}
+	return len(votes) > 0, nil
+}
+
+// HasComments reports whether the post has replies.
+func (api *Client) HasComments(author, permlink string) (bool, error) {
+	replies, err := api.Rpc.Database.GetContentReplies(author, permlink)
+	if err != nil {
+		return false, errors.Wrapf(err, "Error GetContentReplies: ")
+	}
+	return len(replies) > 0, nil
+}
+
+// HasCommented reports whether the user has replied to the post.
+func (api *Client) HasCommented(username, author, permlink string) (bool, error) {
+	replies, err := api.Rpc.Database.GetContentReplies(author, permlink)
+	if err != nil {
+		return false, errors.Wrapf(err, "Error GetContentReplies: ")
+	}
+	for _, v := range replies {
+		if v.Author == username {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// HasReblogged reports whether the rebloger has reblogged the post.
+func (api *Client) HasReblogged(author, permlink, rebloger string) (bool, error) {
+	reblogs, err := api.Rpc.Follow.GetRebloggedBy(author, permlink)
+	if err != nil {
+		return false, errors.Wrapf(err, "Error GetRebloggedBy: ")
+	}
+	for _, v := range reblogs {
+		if v == rebloger {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// IsFollowing reports whether the follower follows the blog of following.
+func (api *Client) IsFollowing(follower, following string) (bool, error) {
+	follows, err := api.Rpc.Follow.GetFollowing(follower, following, "blog", 1)
+	if err != nil {
+		return false, errors.Wrapf(err, "Error GetFollowing: ")
+	}
+	if len(follows) == 0 {
+		return false, nil
+	}
+	return follows[0].Follower == follower && follows[0].Following == following, nil
+}
+
+// PostExists reports whether the post or comment exists.
+func (api *Client) PostExists(author, permlink string) (bool, error) {
+	content, err := api.Rpc.Database.GetContent(author, permlink)
+	if err != nil {
+		return false, errors.Wrapf(err, "Error GetContent: ")
+	}
+	return content.Author == author && content.Permlink == permlink, nil
+}
+
+// IsFirstPost reports whether the user has published at most one post before today.
+func (api *Client) IsFirstPost(username string) (bool, error) {
+	posts, err := api.Rpc.Database.GetDiscussionsByAuthorBeforeDate(username, "", time.Now().Format("2006-01-02T00:00:00"), 100)
+	if err != nil {
+		return false, errors.Wrapf(err, "Error GetDiscussionsByAuthorBeforeDate: ")
+	}
+	return len(posts) <= 1, nil
+}
+
+// AccountExists reports whether the account exists.
+func (api *Client) AccountExists(username string) (bool, error) {
+	accounts, err := api.Rpc.Database.GetAccounts([]string{username})
+	if err != nil {
+		return false, errors.Wrapf(err, "Error GetAccounts: ")
+	}
+	return len(accounts) == 1, nil
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	// Stdlib
+	"testing"
+
+	// Vendor
+	"github.com/pkg/errors"
+
+	// RPC
+	"github.com/asuleymanov/rpc"
+)
+
+var errNodeDown = errors.New("node is down")
+
+// flakyThread fails the calls of the methods in down.
+type flakyThread struct {
+	*fakeThread
+	down map[string]bool
+}
+
+func (thread *flakyThread) Call(method string, params, response interface{}) error {
+	if thread.down[method] {
+		return errNodeDown
+	}
+	return thread.fakeThread.Call(method, params, response)
+}
+
+func TestQueries(t *testing.T) {
+	thread := &flakyThread{
+		fakeThread: &fakeThread{
+			posts: map[string]post{
+				"alice/post": {created: "2018-01-01T00:00:00"},
+				"bob/re1":    {parent: "alice/post", created: "2018-01-01T00:01:00"},
+			},
+			replies: map[string][]string{"alice/post": {"bob/re1"}},
+			voters:  map[string][]string{"alice/post": {"bob", "carol"}},
+		},
+		down: map[string]bool{},
+	}
+	client, err := rpc.NewClient(thread)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := &Client{Rpc: client}
+
+	check := func(name string, got bool, err error, expected bool) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+		if got != expected {
+			t.Errorf("%v: expected %v, got %v", name, expected, got)
+		}
+	}
+
+	ok, err := api.HasVoted("alice", "post", "carol")
+	check("HasVoted carol", ok, err, true)
+	ok, err = api.HasVoted("alice", "post", "dave")
+	check("HasVoted dave", ok, err, false)
+	ok, err = api.HasVotes("bob", "re1")
+	check("HasVotes", ok, err, false)
+	ok, err = api.HasComments("alice", "post")
+	check("HasComments", ok, err, true)
+	ok, err = api.HasCommented("bob", "alice", "post")
+	check("HasCommented bob", ok, err, true)
+	ok, err = api.HasCommented("carol", "alice", "post")
+	check("HasCommented carol", ok, err, false)
+	ok, err = api.PostExists("bob", "re1")
+	check("PostExists", ok, err, true)
+	ok, err = api.PostExists("bob", "re2")
+	check("PostExists missing", ok, err, false)
+
+	vote, err := api.GetVote("alice", "post", "bob")
+	if err != nil || vote == nil || vote.Voter != "bob" {
+		t.Errorf("unexpected vote %+v, error %v", vote, err)
+	}
+
+	// A failing node is an error, not a "no".
+	thread.down["get_active_votes"] = true
+	thread.down["get_content"] = true
+	if _, err := api.HasVoted("alice", "post", "carol"); errors.Cause(err) != errNodeDown {
+		t.Errorf("expected the node error, got %v", err)
+	}
+	if _, err := api.PostExists("alice", "post"); errors.Cause(err) != errNodeDown {
+		t.Errorf("expected the node error, got %v", err)
+	}
+	if api.Verify_Voter("alice", "post", "carol") {
+		t.Error("expected the deprecated Verify_Voter to return false")
+	}
+	// Vote must not broadcast when it cannot tell whether the user has voted.
+	if err := api.Vote("carol", "alice", "post", 10000); errors.Cause(err) != errNodeDown {
+		t.Errorf("expected the node error, got %v", err)
+	}
+}
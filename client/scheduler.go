@@ -0,0 +1,390 @@
+package client
+
+import (
+	// Stdlib
+	"bytes"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	// Vendor
+	"github.com/pkg/errors"
+
+	// RPC
+	"github.com/asuleymanov/rpc/apis/database"
+	"github.com/asuleymanov/rpc/encoding/transaction"
+	"github.com/asuleymanov/rpc/types"
+)
+
+// Chain limits enforced by steemd 0.18.
+const (
+	MinRootCommentInterval = 5 * time.Minute
+	MinReplyInterval       = 20 * time.Second
+	MinVoteInterval        = 3 * time.Second
+)
+
+// Size of the signature and the transaction header in bytes,
+// used to estimate the size of a signed transaction.
+const trxOverhead = 80
+
+// Number of times a broadcast is retried after failing on bandwidth.
+const bandwidthRetries = 3
+
+// bandwidthBackoff is the wait before the first bandwidth retry,
+// it doubles with every further retry.
+var bandwidthBackoff = 3 * time.Second
+
+// Messages of the steemd assertions failing a transaction for lack of bandwidth,
+// the first one is used since 0.17, the second one before.
+var bandwidthErrors = []string{
+	"bandwidth limit exceeded. Please wait to transact or power up STEEM.",
+	"Account exceeded maximum allowed bandwidth per vesting share.",
+}
+
+var ErrSchedulerClosed = errors.New("broadcast scheduler closed")
+
+// BroadcastResult is the result of a scheduled broadcast.
+type BroadcastResult struct {
+	Resp *BResp
+	Err  error
+}
+
+// BroadcastScheduler queues transactions per account and broadcasts them
+// as soon as the chain limits allow it, instead of letting them fail.
+//
+// Transactions of a single account are broadcast in the order they were scheduled,
+// transactions of different accounts do not block each other.
+type BroadcastScheduler struct {
+	api *Client
+
+	mu     sync.Mutex
+	queues map[string]*accountQueue
+	done   chan struct{}
+	closed bool
+	wg     sync.WaitGroup
+	// senders counts the Schedule calls enqueueing a job,
+	// Close waits for them before failing the jobs left in the queues.
+	senders sync.WaitGroup
+}
+
+type accountQueue struct {
+	jobs         chan *broadcastJob
+	lastRootPost time.Time
+	lastPost     time.Time
+	lastVote     time.Time
+}
+
+type broadcastJob struct {
+	ops    []types.Operation
+	result chan BroadcastResult
+}
+
+// NewBroadcastScheduler creates a scheduler broadcasting through api.
+func (api *Client) NewBroadcastScheduler() *BroadcastScheduler {
+	return &BroadcastScheduler{
+		api:    api,
+		queues: make(map[string]*accountQueue),
+		done:   make(chan struct{}),
+	}
+}
+
+// Schedule queues the operations to be broadcast in a single transaction signed by username.
+// The returned channel receives exactly one result.
+func (s *BroadcastScheduler) Schedule(username string, ops ...types.Operation) <-chan BroadcastResult {
+	job := &broadcastJob{ops: ops, result: make(chan BroadcastResult, 1)}
+	if len(ops) == 0 {
+		job.result <- BroadcastResult{Err: errors.New("no operations to broadcast")}
+		return job.result
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		job.result <- BroadcastResult{Err: ErrSchedulerClosed}
+		return job.result
+	}
+	queue, ok := s.queues[username]
+	if !ok {
+		queue = &accountQueue{jobs: make(chan *broadcastJob, 64)}
+		s.queues[username] = queue
+		s.wg.Add(1)
+		go s.worker(username, queue)
+	}
+	s.senders.Add(1)
+	s.mu.Unlock()
+	defer s.senders.Done()
+
+	select {
+	case queue.jobs <- job:
+	case <-s.done:
+		job.result <- BroadcastResult{Err: ErrSchedulerClosed}
+	}
+	return job.result
+}
+
+// Close stops the scheduler. Transactions not broadcast yet fail with ErrSchedulerClosed.
+func (s *BroadcastScheduler) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.done)
+	s.mu.Unlock()
+
+	s.wg.Wait()
+
+	// A Schedule call racing with Close can still enqueue a job after its worker exited.
+	s.senders.Wait()
+	for _, queue := range s.queues {
+		queue.fail()
+	}
+}
+
+func (s *BroadcastScheduler) worker(username string, queue *accountQueue) {
+	defer s.wg.Done()
+	for {
+		select {
+		case job := <-queue.jobs:
+			resp, err := s.broadcast(username, queue, job.ops)
+			job.result <- BroadcastResult{resp, err}
+		case <-s.done:
+			queue.fail()
+			return
+		}
+	}
+}
+
+// fail fails the queued jobs with ErrSchedulerClosed.
+func (queue *accountQueue) fail() {
+	for {
+		select {
+		case job := <-queue.jobs:
+			job.result <- BroadcastResult{Err: ErrSchedulerClosed}
+		default:
+			return
+		}
+	}
+}
+
+func (s *BroadcastScheduler) broadcast(username string, queue *accountQueue, ops []types.Operation) (*BResp, error) {
+	for attempt := 0; ; attempt++ {
+		delay, err := s.delay(username, queue, ops)
+		if err != nil {
+			return nil, err
+		}
+		if !s.sleep(delay) {
+			return nil, ErrSchedulerClosed
+		}
+
+		resp, err := s.api.Send_Arr_Trx(username, ops)
+		if err != nil {
+			if attempt < bandwidthRetries && isBandwidthError(err) {
+				// The node rejected the transaction although the estimated bandwidth allowed it,
+				// give the average bandwidth some time to decay before trying again.
+				if !s.sleep(bandwidthBackoff << uint(attempt)) {
+					return nil, ErrSchedulerClosed
+				}
+				continue
+			}
+			return nil, err
+		}
+
+		now := time.Now()
+		for _, op := range ops {
+			switch op := op.(type) {
+			case *types.CommentOperation:
+				queue.lastPost = now
+				if op.ParentAuthor == "" {
+					queue.lastRootPost = now
+				}
+			case *types.VoteOperation:
+				queue.lastVote = now
+			}
+		}
+		return resp, nil
+	}
+}
+
+func isBandwidthError(err error) bool {
+	for _, msg := range bandwidthErrors {
+		if strings.Contains(err.Error(), msg) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *BroadcastScheduler) sleep(delay time.Duration) bool {
+	if delay <= 0 {
+		return true
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+// delay returns how long to wait before ops can be broadcast by username.
+func (s *BroadcastScheduler) delay(username string, queue *accountQueue, ops []types.Operation) (time.Duration, error) {
+	accounts, err := s.api.Rpc.Database.GetAccounts([]string{username})
+	if err != nil {
+		return 0, errors.Wrapf(err, "Error GetAccounts: ")
+	}
+	if len(accounts) == 0 {
+		return 0, errors.Errorf("account %v not found", username)
+	}
+	acc := accounts[0]
+
+	lastRootPost := latest(queue.lastRootPost, acc.LastRootPost)
+	lastPost := latest(queue.lastPost, acc.LastPost)
+	lastVote := latest(queue.lastVote, acc.LastVoteTime)
+
+	var next time.Time
+	bandwidthType := database.BandwidthForum
+	for _, op := range ops {
+		switch op := op.(type) {
+		case *types.CommentOperation:
+			content, err := s.api.Rpc.Database.GetContent(op.Author, op.Permlink)
+			if err != nil {
+				return 0, errors.Wrapf(err, "Error GetContent: ")
+			}
+			if content.Author != "" {
+				// Editing an existing comment is not limited.
+				continue
+			}
+			if op.ParentAuthor == "" {
+				next = later(next, lastRootPost.Add(MinRootCommentInterval))
+			} else {
+				next = later(next, lastPost.Add(MinReplyInterval))
+			}
+		case *types.VoteOperation:
+			next = later(next, lastVote.Add(MinVoteInterval))
+		case *types.LimitOrderCreateOperation, *types.LimitOrderCancelOperation,
+			*types.TransferOperation, *types.TransferToVestingOperation, *types.ConvertOperation:
+			bandwidthType = database.BandwidthMarket
+		}
+	}
+	delay := time.Until(next)
+
+	bwDelay, err := s.bandwidthDelay(username, acc.VestingShares, bandwidthType, ops)
+	if err != nil {
+		return 0, err
+	}
+	if bwDelay > delay {
+		delay = bwDelay
+	}
+	return delay, nil
+}
+
+// bandwidthDelay returns how long it takes for the average bandwidth
+// of the account to decay enough for the transaction to fit in.
+func (s *BroadcastScheduler) bandwidthDelay(username, vestingShares string, bandwidthType uint32, ops []types.Operation) (time.Duration, error) {
+	config, err := s.api.Rpc.Database.GetConfig()
+	if err != nil {
+		return 0, errors.Wrapf(err, "Error GetConfig: ")
+	}
+	props, err := s.api.Rpc.Database.GetDynamicGlobalProperties()
+	if err != nil {
+		return 0, errors.Wrapf(err, "Error get DynamicGlobalProperties: ")
+	}
+	bandwidth, err := s.api.Rpc.Database.GetAccountBandwidth(username, bandwidthType)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Error GetAccountBandwidth: ")
+	}
+	if bandwidth == nil || bandwidth.AverageBandwidth == nil || bandwidth.AverageBandwidth.Int == nil ||
+		bandwidth.LastBandwidthUpdate == nil || bandwidth.LastBandwidthUpdate.Time == nil {
+		// No bandwidth used yet.
+		return 0, nil
+	}
+
+	if config.SteemitBandwidthAverageWindowSeconds == nil || config.SteemitBandwidthPrecision == nil ||
+		props.MaxVirtualBandwidth == nil {
+		return 0, nil
+	}
+
+	vshares, err := assetAmount(vestingShares)
+	if err != nil {
+		return 0, err
+	}
+	totalVshares, err := assetAmount(props.TotalVestingShares)
+	if err != nil {
+		return 0, err
+	}
+	if totalVshares.Sign() == 0 {
+		return 0, nil
+	}
+
+	size, err := estimateSize(ops)
+	if err != nil {
+		return 0, err
+	}
+
+	// allowed = vshares * max_virtual_bandwidth / total_vshares
+	allowed := new(big.Rat).SetFrac(new(big.Int).Mul(vshares, props.MaxVirtualBandwidth.Int), totalVshares)
+	// The transaction itself adds size * precision to the average.
+	trx := new(big.Rat).SetInt(new(big.Int).Mul(big.NewInt(int64(size)), config.SteemitBandwidthPrecision.Int))
+
+	room := new(big.Rat).Sub(allowed, trx)
+	if room.Sign() <= 0 {
+		return 0, errors.Errorf("account %v does not have enough vesting shares to broadcast the transaction", username)
+	}
+
+	avg := new(big.Rat).SetInt(bandwidth.AverageBandwidth.Int)
+	if avg.Cmp(room) <= 0 {
+		return 0, nil
+	}
+
+	// The average decays linearly over the window:
+	//   avg * (window - t) / window <= room  =>  t >= window * (avg - room) / avg
+	window := new(big.Rat).SetInt(config.SteemitBandwidthAverageWindowSeconds.Int)
+	needed := new(big.Rat).Mul(window, new(big.Rat).Quo(new(big.Rat).Sub(avg, room), avg))
+	seconds, _ := needed.Float64()
+
+	ready := bandwidth.LastBandwidthUpdate.Add(time.Duration(seconds * float64(time.Second)))
+	return time.Until(ready), nil
+}
+
+func estimateSize(ops []types.Operation) (int, error) {
+	now := time.Now()
+	tx := &types.Transaction{Expiration: &types.Time{Time: &now}}
+	for _, op := range ops {
+		tx.PushOperation(op)
+	}
+
+	var buf bytes.Buffer
+	if err := transaction.NewEncoder(&buf).Encode(tx); err != nil {
+		return 0, errors.Wrapf(err, "failed to estimate transaction size")
+	}
+	return buf.Len() + trxOverhead, nil
+}
+
+// assetAmount returns the amount of an asset string like "1.000000 VESTS" in satoshis.
+func assetAmount(asset string) (*big.Int, error) {
+	amount := strings.Replace(strings.Split(asset, " ")[0], ".", "", 1)
+	value, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return nil, errors.Errorf("invalid asset amount: %v", asset)
+	}
+	return value, nil
+}
+
+func latest(local time.Time, remote *types.Time) time.Time {
+	if remote != nil && remote.Time != nil {
+		return later(local, *remote.Time)
+	}
+	return local
+}
+
+func later(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,211 @@
+package client
+
+import (
+	// Stdlib
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	// Vendor
+	"github.com/pkg/errors"
+
+	// RPC
+	"github.com/asuleymanov/rpc"
+	"github.com/asuleymanov/rpc/transactions"
+	"github.com/asuleymanov/rpc/types"
+)
+
+// broadcastThread is a node accepting broadcasts of a single account,
+// the first failures broadcasts are rejected for lack of bandwidth.
+type broadcastThread struct {
+	mu         sync.Mutex
+	lastVote   time.Time
+	failures   int
+	failed     []time.Time
+	broadcasts []time.Time
+}
+
+func (thread *broadcastThread) Call(method string, params, response interface{}) error {
+	thread.mu.Lock()
+	defer thread.mu.Unlock()
+
+	var result interface{}
+	switch method {
+	case "get_accounts":
+		result = []interface{}{map[string]interface{}{
+			"name":           "alice",
+			"vesting_shares": "1000.000000 VESTS",
+			"last_vote_time": thread.lastVote.UTC().Format("2006-01-02T15:04:05"),
+		}}
+	case "get_config":
+		result = map[string]interface{}{}
+	case "get_dynamic_global_properties":
+		result = map[string]interface{}{
+			"head_block_number":    1,
+			"head_block_id":        "0000000109833ce528d5bbfb3f6225b39ee10086",
+			"time":                 time.Now().UTC().Format("2006-01-02T15:04:05"),
+			"total_vesting_shares": "1000000.000000 VESTS",
+		}
+	case "get_account_bandwidth":
+		result = nil
+	case "call":
+		args := params.([]interface{})
+		switch args[1] {
+		case "get_api_by_name":
+			result = 3
+		case "broadcast_transaction_synchronous":
+			if thread.failures > 0 {
+				thread.failures--
+				thread.failed = append(thread.failed, time.Now())
+				return errors.New(`10 assert_exception: Assert Exception
+has_bandwidth: Account: alice bandwidth limit exceeded. Please wait to transact or power up STEEM.`)
+			}
+			thread.broadcasts = append(thread.broadcasts, time.Now())
+			result = map[string]interface{}{"id": "abc", "block_num": len(thread.broadcasts)}
+		default:
+			return fmt.Errorf("unexpected method %v", args[1])
+		}
+	default:
+		return fmt.Errorf("unexpected method %v", method)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, response)
+}
+
+func (thread *broadcastThread) Close() error {
+	return nil
+}
+
+func newTestScheduler(t *testing.T, thread *broadcastThread) *BroadcastScheduler {
+	Key_List["alice"] = Keys{PKey: "5JWHY5DxTF6qN5grTtChDCYBmWHfY9zaSsw4CxEKN5eZpH9iBma"}
+	client, err := rpc.NewClient(thread)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := &Client{Rpc: client, Chain: transactions.SteemChain}
+	return api.NewBroadcastScheduler()
+}
+
+func vote(permlink string) *types.VoteOperation {
+	return &types.VoteOperation{Voter: "alice", Author: "bob", Permlink: permlink, Weight: 10000}
+}
+
+func wait(t *testing.T, result <-chan BroadcastResult) BroadcastResult {
+	t.Helper()
+	select {
+	case res := <-result:
+		return res
+	case <-time.After(10 * time.Second):
+		t.Fatal("no broadcast result")
+		return BroadcastResult{}
+	}
+}
+
+func TestBroadcastScheduler_RateLimit(t *testing.T) {
+	thread := &broadcastThread{lastVote: time.Now()}
+	s := newTestScheduler(t, thread)
+	defer s.Close()
+
+	first := s.Schedule("alice", vote("first"))
+	second := s.Schedule("alice", vote("second"))
+	for _, result := range []<-chan BroadcastResult{first, second} {
+		if res := wait(t, result); res.Err != nil {
+			t.Fatal(res.Err)
+		}
+	}
+
+	thread.mu.Lock()
+	defer thread.mu.Unlock()
+	if len(thread.broadcasts) != 2 {
+		t.Fatalf("expected 2 broadcasts, got %v", len(thread.broadcasts))
+	}
+	// The node reports the last vote time in seconds.
+	if earliest := thread.lastVote.Truncate(time.Second).Add(MinVoteInterval); thread.broadcasts[0].Before(earliest) {
+		t.Errorf("first vote broadcast at %v, before %v", thread.broadcasts[0], earliest)
+	}
+	if gap := thread.broadcasts[1].Sub(thread.broadcasts[0]); gap < MinVoteInterval {
+		t.Errorf("votes broadcast %v apart, less than %v", gap, MinVoteInterval)
+	}
+}
+
+func TestBroadcastScheduler_BandwidthRetry(t *testing.T) {
+	defer func(backoff time.Duration) { bandwidthBackoff = backoff }(bandwidthBackoff)
+	bandwidthBackoff = 50 * time.Millisecond
+
+	thread := &broadcastThread{failures: bandwidthRetries}
+	s := newTestScheduler(t, thread)
+	defer s.Close()
+
+	res := wait(t, s.Schedule("alice", vote("post")))
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	if res.Resp == nil || res.Resp.BlockNum != 1 {
+		t.Errorf("unexpected response %+v", res.Resp)
+	}
+
+	thread.mu.Lock()
+	attempts := append(append([]time.Time{}, thread.failed...), thread.broadcasts...)
+	thread.mu.Unlock()
+	if len(attempts) != bandwidthRetries+1 {
+		t.Fatalf("expected %v attempts, got %v", bandwidthRetries+1, len(attempts))
+	}
+	for i := 1; i < len(attempts); i++ {
+		backoff := bandwidthBackoff << uint(i-1)
+		if gap := attempts[i].Sub(attempts[i-1]); gap < backoff {
+			t.Errorf("retry %v sent %v after the failure, expected at least %v", i, gap, backoff)
+		}
+	}
+
+	// A fresh scheduler, so the vote is not delayed by MinVoteInterval.
+	exhausted := newTestScheduler(t, &broadcastThread{failures: bandwidthRetries + 1})
+	defer exhausted.Close()
+	if res := wait(t, exhausted.Schedule("alice", vote("other"))); res.Err == nil {
+		t.Error("expected an error after the retries are exhausted")
+	}
+}
+
+func TestBroadcastScheduler_ClosePending(t *testing.T) {
+	// The first vote waits for MinVoteInterval, so the jobs are still queued on Close.
+	thread := &broadcastThread{lastVote: time.Now()}
+	s := newTestScheduler(t, thread)
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results []<-chan BroadcastResult
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result := s.Schedule("alice", vote(fmt.Sprintf("post-%v", i)))
+			mu.Lock()
+			results = append(results, result)
+			mu.Unlock()
+		}(i)
+	}
+	time.Sleep(10 * time.Millisecond)
+	s.Close()
+	wg.Wait()
+
+	for _, result := range results {
+		if res := wait(t, result); res.Err != ErrSchedulerClosed {
+			t.Errorf("expected ErrSchedulerClosed, got %v", res.Err)
+		}
+	}
+	if res := wait(t, s.Schedule("alice", vote("late"))); res.Err != ErrSchedulerClosed {
+		t.Errorf("expected ErrSchedulerClosed, got %v", res.Err)
+	}
+
+	thread.mu.Lock()
+	defer thread.mu.Unlock()
+	if len(thread.broadcasts) != 0 {
+		t.Errorf("expected no broadcasts, got %v", len(thread.broadcasts))
+	}
+}
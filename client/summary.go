@@ -0,0 +1,270 @@
+package client
+
+import (
+	// Stdlib
+	"math"
+	"math/big"
+	"time"
+
+	// Vendor
+	"github.com/pkg/errors"
+
+	// RPC
+	"github.com/asuleymanov/rpc/apis/database"
+	"github.com/asuleymanov/rpc/rewards"
+	"github.com/asuleymanov/rpc/types"
+)
+
+// VestingWithdrawInterval is the time between two power down payments.
+const VestingWithdrawInterval = 7 * 24 * time.Hour
+
+// AccountSummary is the stake, balances and reputation of an account.
+// All the amounts are exact, Steem Power is rounded down to 0.001 STEEM.
+type AccountSummary struct {
+	Name string
+	// Reputation is the score shown by the frontends, 25 for new accounts.
+	Reputation    float64
+	RawReputation int64
+	// VotingPower is the current voting power in basis points.
+	VotingPower int
+
+	// SteemPerMvest is the number of STEEM one million VESTS are worth.
+	SteemPerMvest *big.Rat
+
+	Balance           *types.Asset
+	SBDBalance        *types.Asset
+	SavingsBalance    *types.Asset
+	SavingsSBDBalance *types.Asset
+
+	VestingShares          *types.Asset
+	DelegatedVestingShares *types.Asset
+	ReceivedVestingShares  *types.Asset
+	// EffectiveVestingShares is VestingShares - DelegatedVestingShares + ReceivedVestingShares.
+	EffectiveVestingShares *types.Asset
+
+	SteemPower          *types.Asset
+	DelegatedSteemPower *types.Asset
+	ReceivedSteemPower  *types.Asset
+	EffectiveSteemPower *types.Asset
+
+	// PowerDown is nil when the account is not powering down.
+	PowerDown *PowerDown
+	// SavingsWithdrawals are the pending withdrawals from the savings.
+	SavingsWithdrawals []*SavingsWithdrawal
+	// WithdrawRoutes are the accounts receiving a part of the power down.
+	WithdrawRoutes []*database.WithdrawRoute
+}
+
+// PowerDown is the state of a power down.
+type PowerDown struct {
+	// Rate is the amount of VESTS withdrawn every week.
+	Rate       *types.Asset
+	RateSteem  *types.Asset
+	ToWithdraw *types.Asset
+	Withdrawn  *types.Asset
+	Remaining  *types.Asset
+	// Schedule lists the remaining payments, the first one at the next withdrawal time.
+	Schedule []*PowerDownPayment
+}
+
+// PowerDownPayment is a single weekly power down payment.
+type PowerDownPayment struct {
+	Time  time.Time
+	Vests *types.Asset
+	Steem *types.Asset
+}
+
+// SavingsWithdrawal is a pending withdrawal from the savings.
+type SavingsWithdrawal struct {
+	RequestID int64
+	To        string
+	Memo      string
+	Amount    *types.Asset
+	Complete  time.Time
+}
+
+// ReputationScore converts a raw reputation to the score shown by the frontends:
+// 25 for new accounts, 9 points more for every tenfold increase of the raw value above 10^9.
+func ReputationScore(raw int64) float64 {
+	if raw == 0 {
+		return 25
+	}
+	abs := math.Abs(float64(raw))
+	score := math.Log10(abs) - 9
+	if score < 0 {
+		score = 0
+	}
+	if raw < 0 {
+		score = -score
+	}
+	return score*9 + 25
+}
+
+// AccountSummary fetches the account and returns its summary.
+func (api *Client) AccountSummary(username string) (*AccountSummary, error) {
+	props, err := api.Rpc.Database.GetDynamicGlobalProperties()
+	if err != nil {
+		return nil, err
+	}
+	acc, err := api.getAccount(username)
+	if err != nil {
+		return nil, err
+	}
+	savings, err := api.Rpc.Database.GetSavingsWithdrawFrom(username)
+	if err != nil {
+		return nil, err
+	}
+	routes, err := api.Rpc.Database.GetWithdrawRoutes(username, database.WithdrawRouteOutgoing)
+	if err != nil {
+		return nil, err
+	}
+	return NewAccountSummary(acc, props, savings, routes)
+}
+
+// NewAccountSummary computes the summary of the account from the global properties
+// and its pending savings withdrawals and withdraw routes, which can be nil.
+func NewAccountSummary(acc *database.Account, props *database.DynamicGlobalProperties,
+	savings []*database.SavingsWithdraw, routes []*database.WithdrawRoute) (*AccountSummary, error) {
+	fund, err := parseAsset(props.TotalVersingFundSteem)
+	if err != nil {
+		return nil, err
+	}
+	shares, err := parseAsset(props.TotalVestingShares)
+	if err != nil {
+		return nil, err
+	}
+	if shares.Amount == 0 {
+		return nil, errors.New("total vesting shares are zero")
+	}
+	steemPerVest := new(big.Rat).Quo(fund.Rat(), shares.Rat())
+
+	summary := &AccountSummary{
+		Name:           acc.Name,
+		Reputation:     25,
+		SteemPerMvest:  new(big.Rat).Mul(steemPerVest, big.NewRat(1000000, 1)),
+		WithdrawRoutes: routes,
+	}
+	if acc.Reputation != nil {
+		summary.RawReputation = int64(*acc.Reputation)
+		summary.Reputation = ReputationScore(summary.RawReputation)
+	}
+	now := time.Now()
+	if props.Time != nil && props.Time.Time != nil {
+		now = *props.Time.Time
+	}
+	summary.VotingPower = rewards.VotingPower(acc, now)
+
+	for _, balance := range []struct {
+		dst    **types.Asset
+		value  string
+		symbol string
+	}{
+		{&summary.Balance, acc.Balance, types.SymbolSteem},
+		{&summary.SBDBalance, acc.SbdBalance, types.SymbolSBD},
+		{&summary.SavingsBalance, acc.SavingsBalance, types.SymbolSteem},
+		{&summary.SavingsSBDBalance, acc.SavingsSbdBalance, types.SymbolSBD},
+		{&summary.VestingShares, acc.VestingShares, types.SymbolVests},
+		{&summary.DelegatedVestingShares, acc.DelegatedVestingShares, types.SymbolVests},
+		{&summary.ReceivedVestingShares, acc.ReceivedVestingShares, types.SymbolVests},
+	} {
+		if *balance.dst, err = parseBalance(balance.value, balance.symbol); err != nil {
+			return nil, err
+		}
+	}
+
+	effective := summary.VestingShares.Amount - summary.DelegatedVestingShares.Amount + summary.ReceivedVestingShares.Amount
+	summary.EffectiveVestingShares = &types.Asset{Amount: effective, Precision: 6, Symbol: types.SymbolVests}
+
+	toSteem := func(vests *types.Asset) *types.Asset {
+		return types.NewAsset(new(big.Rat).Mul(vests.Rat(), steemPerVest), 3, types.SymbolSteem)
+	}
+	summary.SteemPower = toSteem(summary.VestingShares)
+	summary.DelegatedSteemPower = toSteem(summary.DelegatedVestingShares)
+	summary.ReceivedSteemPower = toSteem(summary.ReceivedVestingShares)
+	summary.EffectiveSteemPower = toSteem(summary.EffectiveVestingShares)
+
+	if summary.PowerDown, err = newPowerDown(acc, toSteem); err != nil {
+		return nil, err
+	}
+
+	for _, withdraw := range savings {
+		amount, err := parseAsset(withdraw.Amount)
+		if err != nil {
+			return nil, err
+		}
+		w := &SavingsWithdrawal{To: withdraw.To, Memo: withdraw.Memo, Amount: amount}
+		if withdraw.RequestID != nil && withdraw.RequestID.Int != nil {
+			w.RequestID = withdraw.RequestID.Int64()
+		}
+		if withdraw.Complete != nil && withdraw.Complete.Time != nil {
+			w.Complete = *withdraw.Complete.Time
+		}
+		summary.SavingsWithdrawals = append(summary.SavingsWithdrawals, w)
+	}
+	return summary, nil
+}
+
+func newPowerDown(acc *database.Account, toSteem func(*types.Asset) *types.Asset) (*PowerDown, error) {
+	rate, err := parseBalance(acc.VestingWithdrawRate, types.SymbolVests)
+	if err != nil || rate.Amount == 0 {
+		return nil, err
+	}
+	vests := func(amount int64) *types.Asset {
+		return &types.Asset{Amount: amount, Precision: rate.Precision, Symbol: types.SymbolVests}
+	}
+
+	var toWithdraw, withdrawn int64
+	if acc.ToWithdraw != nil && acc.ToWithdraw.Int != nil {
+		toWithdraw = acc.ToWithdraw.Int64()
+	}
+	if acc.Withdrawn != nil && acc.Withdrawn.Int != nil {
+		withdrawn = acc.Withdrawn.Int64()
+	}
+	remaining := toWithdraw - withdrawn
+	if remaining <= 0 {
+		return nil, nil
+	}
+
+	pd := &PowerDown{
+		Rate:       rate,
+		RateSteem:  toSteem(rate),
+		ToWithdraw: vests(toWithdraw),
+		Withdrawn:  vests(withdrawn),
+		Remaining:  vests(remaining),
+	}
+
+	var next time.Time
+	if acc.NextVestingWithdrawal != nil && acc.NextVestingWithdrawal.Time != nil {
+		next = *acc.NextVestingWithdrawal.Time
+	}
+	for left := remaining; left > 0; left -= rate.Amount {
+		amount := rate.Amount
+		if left < amount {
+			amount = left
+		}
+		pd.Schedule = append(pd.Schedule, &PowerDownPayment{
+			Time:  next,
+			Vests: vests(amount),
+			Steem: toSteem(vests(amount)),
+		})
+		next = next.Add(VestingWithdrawInterval)
+	}
+	return pd, nil
+}
+
+// parseBalance parses an account balance, an empty value is zero.
+func parseBalance(value, symbol string) (*types.Asset, error) {
+	if value == "" {
+		precision := uint8(3)
+		if symbol == types.SymbolVests {
+			precision = 6
+		}
+		return &types.Asset{Precision: precision, Symbol: symbol}, nil
+	}
+	return parseAsset(value)
+}
+
+func parseAsset(value string) (*types.Asset, error) {
+	asset, err := types.ParseAsset(value)
+	return asset, errors.Wrap(err, "invalid account summary amount")
+}
@@ -0,0 +1,150 @@
+package client
+
+import (
+	// Stdlib
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	// RPC
+	"github.com/asuleymanov/rpc/apis/database"
+	"github.com/asuleymanov/rpc/apis/follow"
+)
+
+func TestReputationScore(t *testing.T) {
+	cases := []struct {
+		raw      int64
+		expected float64
+	}{
+		{0, 25},
+		{1000000000, 25},
+		{10000000000, 34},
+		{-10000000000, 16},
+		{95832978796820, 69.83},
+	}
+	for _, c := range cases {
+		if score := ReputationScore(c.raw); math.Abs(score-c.expected) > 0.01 {
+			t.Errorf("%v: expected %v, got %v", c.raw, c.expected, score)
+		}
+	}
+
+	// Values above 2^53 are kept exactly.
+	for _, value := range []string{`{"reputation": "9007199254740993"}`, `{"reputation": 9007199254740993}`} {
+		var rep follow.AccountReputation
+		if err := json.Unmarshal([]byte(value), &rep); err != nil {
+			t.Fatal(err)
+		}
+		raw, err := rep.Raw()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if raw != 9007199254740993 {
+			t.Errorf("%v: unexpected raw reputation %v", value, raw)
+		}
+	}
+
+	for _, value := range []string{`{"reputation": "abc"}`, `{"reputation": 1.5}`, `{"reputation": true}`} {
+		var rep follow.AccountReputation
+		if err := json.Unmarshal([]byte(value), &rep); err != nil {
+			t.Fatal(err)
+		}
+		if raw, err := rep.Raw(); err == nil {
+			t.Errorf("%v: expected an error, got %v", value, raw)
+		}
+	}
+}
+
+func TestNewAccountSummary(t *testing.T) {
+	var props database.DynamicGlobalProperties
+	if err := json.Unmarshal([]byte(`{
+		"time": "2018-06-01T12:00:00",
+		"total_vesting_fund_steem": "200000000.000 STEEM",
+		"total_vesting_shares": "400000000000.000000 VESTS"
+	}`), &props); err != nil {
+		t.Fatal(err)
+	}
+	var acc database.Account
+	if err := json.Unmarshal([]byte(`{
+		"name": "alice",
+		"reputation": "10000000000",
+		"voting_power": 9000,
+		"last_vote_time": "2018-06-01T00:00:00",
+		"balance": "1.500 STEEM",
+		"sbd_balance": "2.000 SBD",
+		"savings_balance": "0.000 STEEM",
+		"savings_sbd_balance": "10.000 SBD",
+		"vesting_shares": "20000.000000 VESTS",
+		"delegated_vesting_shares": "5000.000000 VESTS",
+		"received_vesting_shares": "1000.000001 VESTS",
+		"vesting_withdraw_rate": "1000.000000 VESTS",
+		"next_vesting_withdrawal": "2018-06-03T00:00:00",
+		"to_withdraw": 2500000000,
+		"withdrawn": 0
+	}`), &acc); err != nil {
+		t.Fatal(err)
+	}
+	var savings []*database.SavingsWithdraw
+	if err := json.Unmarshal([]byte(`[{
+		"from": "alice", "to": "bob", "memo": "rent", "request_id": 7,
+		"amount": "5.000 SBD", "complete": "2018-06-04T00:00:00"
+	}]`), &savings); err != nil {
+		t.Fatal(err)
+	}
+
+	summary, err := NewAccountSummary(&acc, &props, savings, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.Reputation != 34 || summary.VotingPower != 10000 {
+		t.Errorf("unexpected reputation %v or voting power %v", summary.Reputation, summary.VotingPower)
+	}
+	if summary.SteemPerMvest.Cmp(big.NewRat(500, 1)) != 0 {
+		t.Errorf("expected 500 STEEM per MVESTS, got %v", summary.SteemPerMvest)
+	}
+	amounts := map[string]string{
+		summary.Balance.String():                "1.500 STEEM",
+		summary.SavingsSBDBalance.String():      "10.000 SBD",
+		summary.EffectiveVestingShares.String(): "16000.000001 VESTS",
+		summary.SteemPower.String():             "10.000 STEEM",
+		summary.DelegatedSteemPower.String():    "2.500 STEEM",
+		summary.EffectiveSteemPower.String():    "8.000 STEEM",
+	}
+	for got, expected := range amounts {
+		if got != expected {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	}
+
+	pd := summary.PowerDown
+	if pd == nil {
+		t.Fatal("expected a power down")
+	}
+	if pd.Remaining.String() != "2500.000000 VESTS" || pd.RateSteem.String() != "0.500 STEEM" {
+		t.Errorf("unexpected power down: %v, %v", pd.Remaining, pd.RateSteem)
+	}
+	if len(pd.Schedule) != 3 {
+		t.Fatalf("expected 3 payments, got %v", len(pd.Schedule))
+	}
+	last := pd.Schedule[2]
+	if last.Vests.String() != "500.000000 VESTS" || !last.Time.Equal(time.Date(2018, 6, 17, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected last payment: %v at %v", last.Vests, last.Time)
+	}
+
+	if len(summary.SavingsWithdrawals) != 1 {
+		t.Fatalf("expected 1 savings withdrawal, got %v", len(summary.SavingsWithdrawals))
+	}
+	if w := summary.SavingsWithdrawals[0]; w.RequestID != 7 || w.Amount.String() != "5.000 SBD" || w.To != "bob" {
+		t.Errorf("unexpected savings withdrawal: %+v", w)
+	}
+
+	acc.VestingWithdrawRate = "0.000000 VESTS"
+	if summary, err = NewAccountSummary(&acc, &props, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if summary.PowerDown != nil {
+		t.Errorf("expected no power down, got %+v", summary.PowerDown)
+	}
+}
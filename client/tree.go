@@ -0,0 +1,201 @@
+package client
+
+import (
+	// Stdlib
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	// Vendor
+	"github.com/pkg/errors"
+
+	// RPC
+	"github.com/asuleymanov/rpc/apis/database"
+	"github.com/asuleymanov/rpc/types"
+)
+
+// DefaultTreeConcurrency is the number of get_content_replies calls made in parallel
+// unless set in TreeOptions.
+const DefaultTreeConcurrency = 8
+
+// ErrContentNotFound is returned when the root of a discussion tree does not exist.
+var ErrContentNotFound = errors.New("content not found")
+
+// TreeOrder is the order of the replies in a discussion tree.
+type TreeOrder int
+
+const (
+	// SortByCreated sorts the replies oldest first.
+	SortByCreated TreeOrder = iota
+	// SortByPayout sorts the replies by the total payout, the highest first.
+	SortByPayout
+	// SortByVotes sorts the replies by net votes, the highest first.
+	SortByVotes
+)
+
+// TreeOptions configures GetDiscussionTree. A nil value selects the defaults.
+type TreeOptions struct {
+	// MaxDepth is the number of reply levels fetched under the root, zero means no limit.
+	MaxDepth int
+	// Concurrency is the maximum number of calls in progress.
+	Concurrency int
+	// Order is the order of the children of every node.
+	Order TreeOrder
+}
+
+// DiscussionNode is a node of a discussion tree.
+type DiscussionNode struct {
+	Content  *database.Content
+	Children []*DiscussionNode
+	// Deleted is set for replies returned by the node whose content no longer exists.
+	Deleted bool
+	// Truncated is set when the node has replies not fetched because of MaxDepth.
+	Truncated bool
+}
+
+// GetDiscussionTree fetches the whole discussion under author/permlink.
+//
+// The tree is fetched level by level, the replies of all the nodes of a level
+// are requested in parallel. The result does not depend on the order in which
+// the responses arrive: replies referenced more than once, e.g. because of a cycle,
+// are attached to the first parent in tree order only, replies that do not point
+// back to their parent are dropped.
+func (api *Client) GetDiscussionTree(author, permlink string, opts *TreeOptions) (*DiscussionNode, error) {
+	var options TreeOptions
+	if opts != nil {
+		options = *opts
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = DefaultTreeConcurrency
+	}
+
+	content, err := api.Rpc.Database.GetContent(author, permlink)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error GetContent: ")
+	}
+	if content.Author == "" {
+		return nil, errors.Wrapf(ErrContentNotFound, "%v/%v", author, permlink)
+	}
+
+	root := &DiscussionNode{Content: content}
+	seen := map[string]bool{contentKey(content.Author, content.Permlink): true}
+	level := []*DiscussionNode{root}
+	for depth := 0; len(level) > 0; depth++ {
+		if options.MaxDepth > 0 && depth == options.MaxDepth {
+			for _, node := range level {
+				node.Truncated = intValue(node.Content.Children).Sign() > 0
+			}
+			break
+		}
+
+		replies, err := api.fetchReplies(level, options.Concurrency)
+		if err != nil {
+			return nil, err
+		}
+
+		var next []*DiscussionNode
+		for i, node := range level {
+			for _, reply := range replies[i] {
+				if reply.Author == "" {
+					node.Children = append(node.Children, &DiscussionNode{Content: reply, Deleted: true})
+					continue
+				}
+				if reply.ParentAuthor != node.Content.Author || reply.ParentPermlink != node.Content.Permlink {
+					continue
+				}
+				key := contentKey(reply.Author, reply.Permlink)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				node.Children = append(node.Children, &DiscussionNode{Content: reply})
+			}
+			sortNodes(node.Children, options.Order)
+			for _, child := range node.Children {
+				if !child.Deleted {
+					next = append(next, child)
+				}
+			}
+		}
+		level = next
+	}
+	return root, nil
+}
+
+// fetchReplies returns the replies of every node, in the order of the nodes.
+func (api *Client) fetchReplies(nodes []*DiscussionNode, concurrency int) ([][]*database.Content, error) {
+	var (
+		replies = make([][]*database.Content, len(nodes))
+		errs    = make([]error, len(nodes))
+		sem     = make(chan struct{}, concurrency)
+		wg      sync.WaitGroup
+	)
+	for i, node := range nodes {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, content *database.Content) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			replies[i], errs[i] = api.Rpc.Database.GetContentReplies(content.Author, content.Permlink)
+		}(i, node.Content)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error GetContentReplies %v/%v: ", nodes[i].Content.Author, nodes[i].Content.Permlink)
+		}
+	}
+	return replies, nil
+}
+
+func sortNodes(nodes []*DiscussionNode, order TreeOrder) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].Deleted != nodes[j].Deleted {
+			return nodes[j].Deleted
+		}
+		a, b := nodes[i].Content, nodes[j].Content
+		switch order {
+		case SortByPayout:
+			if c := contentPayout(a).Cmp(contentPayout(b)); c != 0 {
+				return c > 0
+			}
+		case SortByVotes:
+			if c := intValue(a.NetVotes).Cmp(intValue(b.NetVotes)); c != 0 {
+				return c > 0
+			}
+		}
+		if ca, cb := a.Created, b.Created; ca != nil && ca.Time != nil && cb != nil && cb.Time != nil && !ca.Equal(*cb.Time) {
+			return ca.Before(*cb.Time)
+		}
+		return contentKey(a.Author, a.Permlink) < contentKey(b.Author, b.Permlink)
+	})
+}
+
+// contentPayout returns the paid and pending payout of the content in SBD satoshis.
+func contentPayout(content *database.Content) *big.Int {
+	total := new(big.Int)
+	for _, value := range []string{content.TotalPayoutValue, content.CuratorPayoutValue, content.PendingPayoutValue} {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		if amount, err := assetAmount(value); err == nil {
+			total.Add(total, amount)
+		}
+	}
+	return total
+}
+
+func intValue(value *types.Int) *big.Int {
+	if value == nil || value.Int == nil {
+		return new(big.Int)
+	}
+	return value.Int
+}
+
+func contentKey(author, permlink string) string {
+	return author + "/" + permlink
+}
@@ -0,0 +1,150 @@
+package client
+
+import (
+	// Stdlib
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	// RPC
+	"github.com/asuleymanov/rpc"
+)
+
+type post struct {
+	parent  string
+	created string
+	votes   int
+	title   string
+	body    string
+}
+
+// fakeThread serves get_content, get_content_replies and get_active_votes from a map of posts.
+type fakeThread struct {
+	mu      sync.Mutex
+	posts   map[string]post
+	replies map[string][]string
+	voters  map[string][]string
+}
+
+func (thread *fakeThread) content(key string) map[string]interface{} {
+	p, ok := thread.posts[key]
+	if !ok {
+		return map[string]interface{}{"author": ""}
+	}
+	author := strings.Split(key, "/")
+	parent := []string{"", "steemit"}
+	if p.parent != "" {
+		parent = strings.Split(p.parent, "/")
+	}
+	return map[string]interface{}{
+		"author":          author[0],
+		"permlink":        author[1],
+		"parent_author":   parent[0],
+		"parent_permlink": parent[1],
+		"created":         p.created,
+		"net_votes":       p.votes,
+		"children":        len(thread.replies[key]),
+		"title":           p.title,
+		"body":            p.body,
+	}
+}
+
+func (thread *fakeThread) Call(method string, params, response interface{}) error {
+	thread.mu.Lock()
+	defer thread.mu.Unlock()
+
+	args := params.([]string)
+	key := args[0] + "/" + args[1]
+	var result interface{}
+	switch method {
+	case "get_content":
+		result = thread.content(key)
+	case "get_content_replies":
+		var replies []interface{}
+		for _, reply := range thread.replies[key] {
+			replies = append(replies, thread.content(reply))
+		}
+		result = replies
+	case "get_active_votes":
+		var votes []interface{}
+		for _, voter := range thread.voters[key] {
+			votes = append(votes, map[string]interface{}{"voter": voter})
+		}
+		result = votes
+	default:
+		return fmt.Errorf("unexpected method %v", method)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, response)
+}
+
+func (thread *fakeThread) Close() error {
+	return nil
+}
+
+func dump(node *DiscussionNode, depth int, lines *[]string) {
+	line := strings.Repeat(" ", depth) + node.Content.Author
+	if node.Deleted {
+		line += " (deleted)"
+	}
+	if node.Truncated {
+		line += " (truncated)"
+	}
+	*lines = append(*lines, line)
+	for _, child := range node.Children {
+		dump(child, depth+1, lines)
+	}
+}
+
+func TestGetDiscussionTree(t *testing.T) {
+	thread := &fakeThread{
+		posts: map[string]post{
+			"alice/post": {created: "2018-01-01T00:00:00"},
+			"bob/re1":    {parent: "alice/post", created: "2018-01-01T00:02:00", votes: 1},
+			"carol/re2":  {parent: "alice/post", created: "2018-01-01T00:01:00", votes: 5},
+			"dave/re3":   {parent: "bob/re1", created: "2018-01-01T00:03:00"},
+			"erin/re4":   {parent: "dave/re3", created: "2018-01-01T00:04:00"},
+		},
+		replies: map[string][]string{
+			"alice/post": {"bob/re1", "carol/re2", "gone/re5"},
+			// The cycle points back to the root, dave/re3 is also listed under the wrong parent.
+			"bob/re1":   {"dave/re3", "alice/post"},
+			"carol/re2": {"dave/re3"},
+			"dave/re3":  {"erin/re4"},
+		},
+	}
+	client, err := rpc.NewClient(thread)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := &Client{Rpc: client}
+
+	cases := []struct {
+		options  *TreeOptions
+		expected []string
+	}{
+		{nil, []string{"alice", " carol", " bob", "  dave", "   erin", "  (deleted)"}},
+		{&TreeOptions{Order: SortByVotes}, []string{"alice", " carol", " bob", "  dave", "   erin", "  (deleted)"}},
+		{&TreeOptions{MaxDepth: 2, Concurrency: 1}, []string{"alice", " carol", " bob", "  dave (truncated)", "  (deleted)"}},
+	}
+	for i, c := range cases {
+		root, err := api.GetDiscussionTree("alice", "post", c.options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var lines []string
+		dump(root, 0, &lines)
+		if got, expected := strings.Join(lines, "\n"), strings.Join(c.expected, "\n"); got != expected {
+			t.Errorf("case %v: expected\n%v\ngot\n%v", i, expected, got)
+		}
+	}
+
+	if _, err := api.GetDiscussionTree("alice", "missing", nil); err == nil {
+		t.Error("expected an error for a missing root")
+	}
+}
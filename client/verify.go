@@ -3,162 +3,79 @@ package client
 import (
 	// Stdlib
 	"log"
-	"time"
-
-	// Vendor
-	"github.com/pkg/errors"
 )
 
-//We check whether there is a voter on the list of those who have already voted
-func (api *Client) Verify_Voter_Weight(author, permlink, voter string, weight int) bool {
-	ans, err := api.Rpc.Database.GetActiveVotes(author, permlink)
+// The Verify_* functions return false both for "no" and when the node fails,
+// only logging the error. Use the functions of query.go instead.
+
+// logVerify logs the error as is, the functions of query.go already add the context.
+func logVerify(ok bool, err error) bool {
 	if err != nil {
-		log.Println(errors.Wrapf(err, "Error Verify Voter: "))
-		return false
-	} else {
-		for _, v := range ans {
-			if v.Voter == voter && v.Percent == weight {
-				return true
-			}
-		}
-		return false
+		log.Println(err)
 	}
+	return ok
+}
+
+// We check whether there is a voter on the list of those who have already voted
+//
+// Deprecated: use GetVote, which reports RPC errors.
+func (api *Client) Verify_Voter_Weight(author, permlink, voter string, weight int) bool {
+	vote, err := api.GetVote(author, permlink, voter)
+	return logVerify(vote != nil && vote.Percent == weight, err)
 }
 
+// Deprecated: use HasVoted, which reports RPC errors.
 func (api *Client) Verify_Voter(author, permlink, voter string) bool {
-	ans, err := api.Rpc.Database.GetActiveVotes(author, permlink)
-	if err != nil {
-		log.Println(errors.Wrapf(err, "Error Verify Voter: "))
-		return false
-	} else {
-		for _, v := range ans {
-			if v.Voter == voter {
-				return true
-			}
-		}
-		return false
-	}
+	ok, err := api.HasVoted(author, permlink, voter)
+	return logVerify(ok, err)
 }
 
-//We check whether there are voted
+// We check whether there are voted
+//
+// Deprecated: use HasVotes, which reports RPC errors.
 func (api *Client) Verify_Votes(author, permlink string) bool {
-	ans, err := api.Rpc.Database.GetActiveVotes(author, permlink)
-	if err != nil {
-		log.Println(errors.Wrapf(err, "Error Verify Votes: "))
-		return false
-	} else {
-		if len(ans) > 0 {
-			return true
-		} else {
-			return false
-		}
-	}
+	ok, err := api.HasVotes(author, permlink)
+	return logVerify(ok, err)
 }
 
+// Deprecated: use HasComments, which reports RPC errors.
 func (api *Client) Verify_Comments(author, permlink string) bool {
-	ans, err := api.Rpc.Database.GetContentReplies(author, permlink)
-	if err != nil {
-		log.Println(errors.Wrapf(err, "Error Verify Comments: "))
-		return false
-	} else {
-		if len(ans) > 0 {
-			return true
-		} else {
-			return false
-		}
-	}
+	ok, err := api.HasComments(author, permlink)
+	return logVerify(ok, err)
 }
 
+// Deprecated: use HasReblogged, which reports RPC errors.
 func (api *Client) Verify_Reblogs(author, permlink, rebloger string) bool {
-	ans, err := api.Rpc.Follow.GetRebloggedBy(author, permlink)
-	if err != nil {
-		log.Println(errors.Wrapf(err, "Error Verify Reblogs: "))
-		return false
-	} else {
-		for _, v := range ans {
-			if v == rebloger {
-				return true
-			}
-		}
-		return false
-	}
+	ok, err := api.HasReblogged(author, permlink, rebloger)
+	return logVerify(ok, err)
 }
 
+// Deprecated: use IsFollowing, which reports RPC errors.
 func (api *Client) Verify_Follow(follower, following string) bool {
-	ans, err := api.Rpc.Follow.GetFollowing(follower, following, "blog", 1)
-	if err != nil {
-		log.Println(errors.Wrapf(err, "Error Verify Follow: "))
-		return false
-	} else {
-		for _, v := range ans {
-			if (v.Follower == follower) && (v.Following == following) {
-				return true
-			} else {
-				return false
-			}
-		}
-		return false
-	}
+	ok, err := api.IsFollowing(follower, following)
+	return logVerify(ok, err)
 }
 
+// Deprecated: use PostExists, which reports RPC errors.
 func (api *Client) Verify_Post(author, permlink string) bool {
-	ans, err := api.Rpc.Database.GetContent(author, permlink)
-	if err != nil {
-		log.Println(errors.Wrapf(err, "Error Verify Post: "))
-		return false
-	} else {
-		if (ans.Author == author) && (ans.Permlink == permlink) {
-			return true
-		} else {
-			return false
-		}
-		return false
-	}
+	ok, err := api.PostExists(author, permlink)
+	return logVerify(ok, err)
 }
 
+// Deprecated: use IsFirstPost, which reports RPC errors.
 func (api *Client) Verify_First_Post(username string) bool {
-	d := time.Now()
-	cont, err := api.Rpc.Database.GetDiscussionsByAuthorBeforeDate(username, "", d.Format("2006-01-02T00:00:00"), 100)
-	if err != nil {
-		log.Println(errors.Wrapf(err, "Error Verify First Post: "))
-		return false
-	} else {
-		if len(cont) > 1 {
-			return false
-		} else {
-			return true
-		}
-		return false
-	}
+	ok, err := api.IsFirstPost(username)
+	return logVerify(ok, err)
 }
 
+// Deprecated: use HasCommented, which reports RPC errors.
 func (api *Client) Verify_Comment_U(username, author, permlink string) bool {
-	ans, err := api.Rpc.Database.GetContentReplies(author, permlink)
-	if err != nil {
-		log.Println(errors.Wrapf(err, "Error Verify Comments: "))
-		return false
-	} else {
-		if len(ans) > 0 {
-			for _, v := range ans {
-				if v.Author == username {
-					return true
-				}
-			}
-			return false
-		} else {
-			return false
-		}
-	}
+	ok, err := api.HasCommented(username, author, permlink)
+	return logVerify(ok, err)
 }
 
+// Deprecated: use AccountExists, which reports RPC errors.
 func (api *Client) VerifyUser(username string) bool {
-	acc, err := api.Rpc.Database.GetAccounts([]string{username})
-	if err != nil {
-		log.Println(errors.Wrapf(err, "Error Verify User: "))
-		return false
-	} else if len(acc) == 1 {
-		return true
-	} else {
-		return false
-	}
+	ok, err := api.AccountExists(username)
+	return logVerify(ok, err)
 }
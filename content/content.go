@@ -0,0 +1,204 @@
+// Package content analyses post and comment bodies.
+//
+// It extracts the mentioned accounts, the links and the images from Markdown
+// and HTML bodies the way condenser does when building json_metadata,
+// and converts bodies into plain text for excerpts:
+//
+//	analysis := content.Analyze(post.Body)
+//	log.Println(analysis.Users, analysis.Images, content.Summary(post.Body, 140))
+package content
+
+import (
+	// Stdlib
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	// RPC
+	"github.com/asuleymanov/rpc/types"
+)
+
+// Post body formats, as used in json_metadata.
+const (
+	FormatMarkdown = types.FormatMarkdown
+	FormatHTML     = types.FormatHTML
+)
+
+// Analysis is the result of Analyze.
+type Analysis struct {
+	// Format is FormatHTML for bodies wrapped in <html>, FormatMarkdown otherwise.
+	Format string
+	// Users are the mentioned accounts, without the @.
+	Users []string
+	// Links are the linked URLs, images excluded.
+	Links []string
+	// Images are the URLs of the embedded images.
+	Images []string
+}
+
+var (
+	codeBlockRe     = regexp.MustCompile("(?s)```.*?```|~~~.*?~~~|`[^`\n]*`|<code>.*?</code>|<pre>.*?</pre>")
+	markdownImageRe = regexp.MustCompile(`!\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	markdownLinkRe  = regexp.MustCompile(`\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	htmlImageRe     = regexp.MustCompile(`(?i)<img\b[^>]*?\bsrc\s*=\s*["']?([^"'\s>]+)`)
+	htmlLinkRe      = regexp.MustCompile(`(?i)<a\b[^>]*?\bhref\s*=\s*["']?([^"'\s>]+)`)
+	urlRe           = regexp.MustCompile(`https?://[^\s<>"'()\[\]{}]+`)
+	imageURLRe      = regexp.MustCompile(`(?i)\.(?:jpe?g|png|gif|webp|svg)(?:\?[^\s]*)?$`)
+	mentionRe       = regexp.MustCompile(`(?:^|[^a-zA-Z0-9_@/.!#$%&*+~-])@([a-z][a-z0-9.-]{1,30}[a-z0-9])`)
+)
+
+type match struct {
+	pos   int
+	value string
+}
+
+// Analyze extracts the mentions, links and images from the body.
+// Every value is reported once, in the order of the first appearance.
+func Analyze(body string) *Analysis {
+	analysis := &Analysis{Format: FormatMarkdown}
+	if strings.HasPrefix(strings.TrimSpace(strings.ToLower(body)), "<html>") {
+		analysis.Format = FormatHTML
+	}
+
+	// Nothing is extracted from code, matched parts are blanked
+	// so that the positions do not change and nothing is matched twice.
+	text := []byte(blank([]byte(body), codeBlockRe))
+
+	var images, links []match
+	images = append(images, extract(text, markdownImageRe)...)
+	images = append(images, extract(text, htmlImageRe)...)
+	links = append(links, extract(text, markdownLinkRe)...)
+	links = append(links, extract(text, htmlLinkRe)...)
+	for _, m := range extract(text, urlRe) {
+		m.value = strings.TrimRight(m.value, ".,;:!?*_~")
+		if imageURLRe.MatchString(m.value) {
+			images = append(images, m)
+		} else {
+			links = append(links, m)
+		}
+	}
+
+	analysis.Images = unique(images)
+	isImage := make(map[string]bool, len(analysis.Images))
+	for _, image := range analysis.Images {
+		isImage[image] = true
+	}
+	for _, link := range unique(links) {
+		if !isImage[link] {
+			analysis.Links = append(analysis.Links, link)
+		}
+	}
+
+	var users []match
+	for _, loc := range mentionRe.FindAllSubmatchIndex(text, -1) {
+		name := strings.TrimRight(string(text[loc[2]:loc[3]]), ".-")
+		if ValidAccountName(name) {
+			users = append(users, match{loc[2], name})
+		}
+	}
+	analysis.Users = unique(users)
+	return analysis
+}
+
+// ValidAccountName reports whether name is a valid Steem account name.
+func ValidAccountName(name string) bool {
+	return types.ValidateAccountName(name) == nil
+}
+
+// extract returns the first submatch of every match of re, or the whole match
+// when re has no groups, and blanks the matches in text.
+func extract(text []byte, re *regexp.Regexp) []match {
+	var matches []match
+	for _, loc := range re.FindAllSubmatchIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		if len(loc) > 2 {
+			start, end = loc[2], loc[3]
+		}
+		matches = append(matches, match{start, html.UnescapeString(string(text[start:end]))})
+		for i := loc[0]; i < loc[1]; i++ {
+			text[i] = ' '
+		}
+	}
+	return matches
+}
+
+func blank(text []byte, re *regexp.Regexp) string {
+	for _, loc := range re.FindAllIndex(text, -1) {
+		for i := loc[0]; i < loc[1]; i++ {
+			if text[i] != '\n' {
+				text[i] = ' '
+			}
+		}
+	}
+	return string(text)
+}
+
+func unique(matches []match) []string {
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].pos < matches[j].pos
+	})
+	var values []string
+	seen := make(map[string]bool, len(matches))
+	for _, m := range matches {
+		if m.value == "" || seen[m.value] {
+			continue
+		}
+		seen[m.value] = true
+		values = append(values, m.value)
+	}
+	return values
+}
+
+var (
+	htmlCommentRe = regexp.MustCompile(`(?s)<!--.*?-->`)
+	htmlBlockRe   = regexp.MustCompile(`(?i)</?(?:p|div|br|h[1-6]|li|ul|ol|blockquote|center|table|tr|hr)\b[^>]*>`)
+	htmlTagRe     = regexp.MustCompile(`<[^>]+>`)
+	fenceRe       = regexp.MustCompile("(?m)^\\s*(?:```|~~~).*$")
+	headingRe     = regexp.MustCompile(`(?m)^\s{0,3}(?:#{1,6}\s*|>\s?|[-*+]\s+|\d+\.\s+)`)
+	ruleRe        = regexp.MustCompile(`(?m)^\s*(?:[-*_]\s*){3,}$`)
+	plainImageRe  = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	plainLinkRe   = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	emphasisRe    = regexp.MustCompile("\\*\\*|__|~~|[*_`]")
+	whitespaceRe  = regexp.MustCompile(`\s+`)
+)
+
+// Plaintext converts the body into plain text on a single line.
+// Images are dropped, links are replaced by their text and the Markdown
+// and HTML markup is removed.
+func Plaintext(body string) string {
+	text := htmlCommentRe.ReplaceAllString(body, " ")
+	text = htmlBlockRe.ReplaceAllString(text, "\n")
+	text = htmlTagRe.ReplaceAllString(text, "")
+	text = fenceRe.ReplaceAllString(text, "")
+	text = ruleRe.ReplaceAllString(text, "")
+	text = headingRe.ReplaceAllString(text, "")
+	text = plainImageRe.ReplaceAllString(text, "")
+	text = plainLinkRe.ReplaceAllString(text, "$1")
+	text = emphasisRe.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	return strings.TrimSpace(whitespaceRe.ReplaceAllString(text, " "))
+}
+
+// Summary returns the beginning of the plain text of the body, at most maxLength
+// characters long. The text is cut at a word boundary and "..." is appended when truncated.
+func Summary(body string, maxLength int) string {
+	text := Plaintext(body)
+	if utf8.RuneCountInString(text) <= maxLength {
+		return text
+	}
+	if maxLength <= 3 {
+		return string([]rune(text)[:maxLength])
+	}
+
+	runes := []rune(text)[:maxLength-3]
+	cut := len(runes)
+	for i := len(runes) - 1; i > len(runes)/2; i-- {
+		if runes[i] == ' ' {
+			cut = i
+			break
+		}
+	}
+	return strings.TrimRight(string(runes[:cut]), " .,;:") + "..."
+}
@@ -0,0 +1,120 @@
+package content
+
+import (
+	// Stdlib
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const body = `# Hello @alice!
+
+Thanks @bob.smith, @carol and @alice. Mail me at dave@example.com.
+
+![cover](https://example.com/cover.png "Cover")
+![chart](https://steemitimages.com/0x0/chart)
+
+Read [the docs](https://example.com/docs) or https://example.com/faq.
+<a href="https://example.com/html">html</a> <img src="https://example.com/html.jpg">
+
+https://example.com/inline.gif
+
+` + "```\n@ignored https://example.com/code\n```" + `
+
+Not a mention: @ab, @-bad, http://example.com/@erin`
+
+func TestAnalyze(t *testing.T) {
+	analysis := Analyze(body)
+
+	expected := &Analysis{
+		Format: FormatMarkdown,
+		Users:  []string{"alice", "bob.smith", "carol"},
+		Links: []string{
+			"https://example.com/docs",
+			"https://example.com/faq",
+			"https://example.com/html",
+			"http://example.com/@erin",
+		},
+		Images: []string{
+			"https://example.com/cover.png",
+			"https://steemitimages.com/0x0/chart",
+			"https://example.com/html.jpg",
+			"https://example.com/inline.gif",
+		},
+	}
+	if !reflect.DeepEqual(analysis, expected) {
+		t.Errorf("expected %+v, got %+v", expected, analysis)
+	}
+
+	if format := Analyze("<html><p>Hi</p></html>").Format; format != FormatHTML {
+		t.Errorf("expected %v, got %v", FormatHTML, format)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	body := "## Title\n\nSome **bold** and [linked](https://example.com) text &amp; <b>html</b>.\n\n![img](https://example.com/a.png)\n\n- one\n- two"
+
+	if text, expected := Plaintext(body), "Title Some bold and linked text & html. one two"; text != expected {
+		t.Errorf("expected %q, got %q", expected, text)
+	}
+	if summary, expected := Summary(body, 20), "Title Some bold..."; summary != expected {
+		t.Errorf("expected %q, got %q", expected, summary)
+	}
+	if summary := Summary("short", 20); summary != "short" {
+		t.Errorf("expected short, got %q", summary)
+	}
+}
+
+func TestPatch(t *testing.T) {
+	oldBody := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 50)
+	newBody := strings.Replace(oldBody, "lazy dog. The", "lazy cat. The", 1)
+
+	body := EditBody(oldBody, newBody)
+	if len(body) >= len(newBody) {
+		t.Fatalf("expected a patch, got the whole body")
+	}
+	if patched := ApplyPatch(oldBody, body); patched != newBody {
+		t.Errorf("patch not applied correctly: %q", patched)
+	}
+
+	// Short bodies and new content are sent whole, plain bodies replace the old one.
+	if body := EditBody("Hi", "Hello"); body != "Hello" {
+		t.Errorf("expected the whole body, got %q", body)
+	}
+	if body := EditBody(oldBody, oldBody); body != oldBody {
+		t.Errorf("expected the whole body for an unchanged edit")
+	}
+	if patched := ApplyPatch(oldBody, "Replaced"); patched != "Replaced" {
+		t.Errorf("expected the body to be replaced, got %q", patched)
+	}
+}
+
+func TestPatch_Multibyte(t *testing.T) {
+	// The positions and lengths count characters, not bytes.
+	expected := "@@ -1,10 +1,11 @@\n" +
+		" %D0%9F%D1%80%D0%B8%D0%B2%D0%B5%D1%82\n" +
+		"+,\n" +
+		"  %D0%BC%D0%B8%D1%80\n"
+	if patch := CreatePatch("Привет мир", "Привет, мир"); patch != expected {
+		t.Errorf("expected %q, got %q", expected, patch)
+	}
+	if patched := ApplyPatch("Привет мир", expected); patched != "Привет, мир" {
+		t.Errorf("patch not applied correctly: %q", patched)
+	}
+
+	oldBody := strings.Repeat("Съешь же ещё этих мягких французских булок, да выпей чаю. ", 30)
+	newBody := strings.Replace(oldBody, "выпей чаю. Съешь", "выпей кофе ☕. Съешь", 1)
+	newBody = strings.Replace(newBody, "булок", "круассанов", 1)
+
+	body := EditBody(oldBody, newBody)
+	if body == newBody {
+		t.Fatalf("expected a patch, got the whole body")
+	}
+	if patched := ApplyPatch(oldBody, body); patched != newBody {
+		t.Errorf("patch not applied correctly: %q", patched)
+	}
+	// The patch still applies when the text moved.
+	if patched := ApplyPatch("Заголовок\n"+oldBody, body); patched != "Заголовок\n"+newBody {
+		t.Errorf("patch not applied correctly to the moved text: %q", patched)
+	}
+}
@@ -0,0 +1,691 @@
+package content
+
+import (
+	// Stdlib
+	"bytes"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	// Vendor
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// The patches follow diff_match_patch with its default settings, as used by condenser and steemd.
+// Both count characters, while go-diff counts UTF-8 bytes in patches, so go-diff only computes
+// the diffs and the patches are made, encoded and applied over runes here.
+const (
+	patchMargin          = 4
+	patchDeleteThreshold = 0.5
+	matchMaxBits         = 32
+	matchThreshold       = 0.5
+	matchDistance        = 1000
+)
+
+var dmp = diffmatchpatch.New()
+
+var patchHeader = regexp.MustCompile(`^@@ -(\d+),?(\d*) \+(\d+),?(\d*) @@$`)
+
+type diff struct {
+	op   diffmatchpatch.Operation
+	text []rune
+}
+
+type patch struct {
+	diffs            []diff
+	start1, start2   int
+	length1, length2 int
+}
+
+// CreatePatch returns the diff-match-patch patch turning oldBody into newBody,
+// in the text format used by condenser. An empty string is returned
+// when the bodies are equal.
+func CreatePatch(oldBody, newBody string) string {
+	text1 := []rune(oldBody)
+	return patchesToText(makePatches(text1, computeDiffs(text1, []rune(newBody), true, dmp.DiffCleanupSemantic, dmp.DiffCleanupEfficiency)))
+}
+
+// EditBody returns the body to broadcast when editing content from oldBody to newBody.
+// Like condenser, the patch is used only when it is shorter than the new body.
+func EditBody(oldBody, newBody string) string {
+	if oldBody == "" {
+		return newBody
+	}
+	patch := CreatePatch(oldBody, newBody)
+	if patch != "" && utf8.RuneCountInString(patch) < utf8.RuneCountInString(newBody) {
+		return patch
+	}
+	return newBody
+}
+
+// ApplyPatch returns the content body after an edit with the given body was applied
+// to oldBody, the same way steemd does it: a valid patch is applied,
+// any other body replaces the old one.
+//
+// This can be used to reconstruct the history of a post from its comment operations.
+func ApplyPatch(oldBody, body string) string {
+	patches, err := patchesFromText(body)
+	if err != nil || len(patches) == 0 {
+		return body
+	}
+	return string(applyPatches(patches, []rune(oldBody)))
+}
+
+// computeDiffs returns the diffs turning text1 into text2 after the cleanups. go-diff cuts
+// multibyte characters in some of them, so the cleaned up diffs are only used when still valid.
+func computeDiffs(text1, text2 []rune, checklines bool, cleanups ...func([]diffmatchpatch.Diff) []diffmatchpatch.Diff) []diff {
+	diffs := dmp.DiffMainRunes(text1, text2, checklines)
+	if len(diffs) > 2 && len(cleanups) != 0 {
+		cleaned := append([]diffmatchpatch.Diff(nil), diffs...)
+		for _, cleanup := range cleanups {
+			cleaned = cleanup(cleaned)
+		}
+		if validDiffs(cleaned) {
+			diffs = cleaned
+		}
+	}
+	return runeDiffs(diffs)
+}
+
+func validDiffs(diffs []diffmatchpatch.Diff) bool {
+	for _, d := range diffs {
+		if !utf8.ValidString(d.Text) {
+			return false
+		}
+	}
+	return true
+}
+
+func runeDiffs(diffs []diffmatchpatch.Diff) []diff {
+	converted := make([]diff, 0, len(diffs))
+	for _, d := range diffs {
+		if d.Text != "" {
+			converted = append(converted, diff{d.Type, []rune(d.Text)})
+		}
+	}
+	return converted
+}
+
+// makePatches is patch_make for text1 and the diffs turning it into text2.
+func makePatches(text1 []rune, diffs []diff) []patch {
+	var (
+		patches    []patch
+		current    patch
+		charCount1 int
+		charCount2 int
+	)
+	// The patches have a rolling context, each one is made against the text patched so far.
+	prepatch := text1
+	postpatch := text1
+	for i, d := range diffs {
+		if len(current.diffs) == 0 && d.op != diffmatchpatch.DiffEqual {
+			current.start1, current.start2 = charCount1, charCount2
+		}
+
+		switch d.op {
+		case diffmatchpatch.DiffInsert:
+			current.diffs = append(current.diffs, d)
+			current.length2 += len(d.text)
+			postpatch = splice(postpatch, charCount2, 0, d.text)
+		case diffmatchpatch.DiffDelete:
+			current.diffs = append(current.diffs, d)
+			current.length1 += len(d.text)
+			postpatch = splice(postpatch, charCount2, len(d.text), nil)
+		case diffmatchpatch.DiffEqual:
+			if len(d.text) <= 2*patchMargin && len(current.diffs) != 0 && i != len(diffs)-1 {
+				// A small equality inside a patch.
+				current.diffs = append(current.diffs, d)
+				current.length1 += len(d.text)
+				current.length2 += len(d.text)
+			}
+			if len(d.text) >= 2*patchMargin && len(current.diffs) != 0 {
+				patches = append(patches, addContext(current, prepatch))
+				current = patch{}
+				prepatch = postpatch
+				charCount1 = charCount2
+			}
+		}
+
+		if d.op != diffmatchpatch.DiffInsert {
+			charCount1 += len(d.text)
+		}
+		if d.op != diffmatchpatch.DiffDelete {
+			charCount2 += len(d.text)
+		}
+	}
+	if len(current.diffs) != 0 {
+		patches = append(patches, addContext(current, prepatch))
+	}
+	return patches
+}
+
+// addContext extends the patch with the surrounding text until it is unique,
+// but not beyond the pattern length the matching supports.
+func addContext(p patch, text []rune) patch {
+	if len(text) == 0 {
+		return p
+	}
+	source := string(text)
+	pattern := text[p.start2 : p.start2+p.length1]
+	padding := 0
+	for strings.Index(source, string(pattern)) != strings.LastIndex(source, string(pattern)) &&
+		len(pattern) < matchMaxBits-2*patchMargin {
+		padding += patchMargin
+		pattern = text[maxInt(0, p.start2-padding):minInt(len(text), p.start2+p.length1+padding)]
+	}
+	// One more chunk for good luck.
+	padding += patchMargin
+
+	prefix := text[maxInt(0, p.start2-padding):p.start2]
+	if len(prefix) != 0 {
+		p.diffs = append([]diff{{diffmatchpatch.DiffEqual, prefix}}, p.diffs...)
+	}
+	suffix := text[p.start2+p.length1 : minInt(len(text), p.start2+p.length1+padding)]
+	if len(suffix) != 0 {
+		p.diffs = append(p.diffs, diff{diffmatchpatch.DiffEqual, suffix})
+	}
+
+	p.start1 -= len(prefix)
+	p.start2 -= len(prefix)
+	p.length1 += len(prefix) + len(suffix)
+	p.length2 += len(prefix) + len(suffix)
+	return p
+}
+
+// patchesToText is patch_toText, the texts are escaped like encodeURI does.
+func patchesToText(patches []patch) string {
+	var text bytes.Buffer
+	for _, p := range patches {
+		fmt.Fprintf(&text, "@@ -%v +%v @@\n", coords(p.start1, p.length1), coords(p.start2, p.length2))
+		for _, d := range p.diffs {
+			switch d.op {
+			case diffmatchpatch.DiffInsert:
+				text.WriteByte('+')
+			case diffmatchpatch.DiffDelete:
+				text.WriteByte('-')
+			default:
+				text.WriteByte(' ')
+			}
+			text.WriteString(encodeURI(string(d.text)))
+			text.WriteByte('\n')
+		}
+	}
+	return text.String()
+}
+
+// coords formats a range of a patch header, the indices are 1-based.
+func coords(start, length int) string {
+	switch length {
+	case 0:
+		return strconv.Itoa(start) + ",0"
+	case 1:
+		return strconv.Itoa(start + 1)
+	default:
+		return strconv.Itoa(start+1) + "," + strconv.Itoa(length)
+	}
+}
+
+// encodeURI escapes the text the way the JavaScript function does, except spaces.
+func encodeURI(text string) string {
+	var escaped bytes.Buffer
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c == ' ' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+			strings.IndexByte("-_.!~*'();/?:@&=+$,#", c) >= 0 {
+			escaped.WriteByte(c)
+		} else {
+			fmt.Fprintf(&escaped, "%%%02X", c)
+		}
+	}
+	return escaped.String()
+}
+
+// patchesFromText is patch_fromText.
+func patchesFromText(text string) ([]patch, error) {
+	var patches []patch
+	if text == "" {
+		return patches, nil
+	}
+	lines := strings.Split(text, "\n")
+	for i := 0; i < len(lines); {
+		m := patchHeader.FindStringSubmatch(lines[i])
+		if m == nil {
+			return nil, fmt.Errorf("invalid patch header %q", lines[i])
+		}
+		var p patch
+		p.start1, p.length1 = parseCoords(m[1], m[2])
+		p.start2, p.length2 = parseCoords(m[3], m[4])
+		i++
+
+		for ; i < len(lines); i++ {
+			line := lines[i]
+			if line == "" {
+				continue
+			}
+			if line[0] == '@' {
+				break
+			}
+			decoded, err := url.PathUnescape(line[1:])
+			if err != nil {
+				return nil, err
+			}
+			switch line[0] {
+			case '-':
+				p.diffs = append(p.diffs, diff{diffmatchpatch.DiffDelete, []rune(decoded)})
+			case '+':
+				p.diffs = append(p.diffs, diff{diffmatchpatch.DiffInsert, []rune(decoded)})
+			case ' ':
+				p.diffs = append(p.diffs, diff{diffmatchpatch.DiffEqual, []rune(decoded)})
+			default:
+				return nil, fmt.Errorf("invalid patch mode %q", line[0])
+			}
+		}
+		patches = append(patches, p)
+	}
+	return patches, nil
+}
+
+func parseCoords(start, length string) (int, int) {
+	s, _ := strconv.Atoi(start)
+	switch length {
+	case "":
+		return s - 1, 1
+	case "0":
+		return s, 0
+	default:
+		l, _ := strconv.Atoi(length)
+		return s - 1, l
+	}
+}
+
+// applyPatches is patch_apply, patches which cannot be located are skipped.
+func applyPatches(patches []patch, text []rune) []rune {
+	patches = copyPatches(patches)
+	padding := addPadding(patches)
+	text = append(append(append([]rune(nil), padding...), text...), padding...)
+	patches = splitMax(patches)
+
+	// The offset between the expected and the actual location of the previous patch.
+	delta := 0
+	for _, p := range patches {
+		expectedLoc := p.start2 + delta
+		text1 := diffText1(p.diffs)
+		var startLoc int
+		endLoc := -1
+		if len(text1) > matchMaxBits {
+			// splitMax only leaves an oversized pattern for a monster delete.
+			startLoc = matchMain(text, text1[:matchMaxBits], expectedLoc)
+			if startLoc != -1 {
+				endLoc = matchMain(text, text1[len(text1)-matchMaxBits:], expectedLoc+len(text1)-matchMaxBits)
+				if endLoc == -1 || startLoc >= endLoc {
+					startLoc = -1
+				}
+			}
+		} else {
+			startLoc = matchMain(text, text1, expectedLoc)
+		}
+		if startLoc == -1 {
+			delta -= p.length2 - p.length1
+			continue
+		}
+
+		delta = startLoc - expectedLoc
+		var text2 []rune
+		if endLoc == -1 {
+			text2 = text[startLoc:minInt(startLoc+len(text1), len(text))]
+		} else {
+			text2 = text[startLoc:minInt(endLoc+matchMaxBits, len(text))]
+		}
+		if string(text1) == string(text2) {
+			text = splice(text, startLoc, len(text1), diffText2(p.diffs))
+			continue
+		}
+
+		// An imperfect match, a diff gives the equivalent indices.
+		diffs := computeDiffs(text1, text2, false, dmp.DiffCleanupSemanticLossless)
+		if len(text1) > matchMaxBits && float64(levenshtein(diffs))/float64(len(text1)) > patchDeleteThreshold {
+			// The end points match, but the content is unacceptably bad.
+			continue
+		}
+		index1 := 0
+		for _, d := range p.diffs {
+			if d.op != diffmatchpatch.DiffEqual {
+				index2 := xIndex(diffs, index1)
+				switch d.op {
+				case diffmatchpatch.DiffInsert:
+					text = splice(text, startLoc+index2, 0, d.text)
+				case diffmatchpatch.DiffDelete:
+					text = splice(text, startLoc+index2, xIndex(diffs, index1+len(d.text))-index2, nil)
+				}
+			}
+			if d.op != diffmatchpatch.DiffDelete {
+				index1 += len(d.text)
+			}
+		}
+	}
+	return text[len(padding) : len(text)-len(padding)]
+}
+
+func copyPatches(patches []patch) []patch {
+	copied := make([]patch, len(patches))
+	for i, p := range patches {
+		copied[i] = p
+		copied[i].diffs = make([]diff, len(p.diffs))
+		for j, d := range p.diffs {
+			copied[i].diffs[j] = diff{d.op, append([]rune(nil), d.text...)}
+		}
+	}
+	return copied
+}
+
+// addPadding pads the patches so the edges of the text can be matched, returning the padding.
+func addPadding(patches []patch) []rune {
+	padding := make([]rune, patchMargin)
+	for i := range padding {
+		padding[i] = rune(i + 1)
+	}
+	for i := range patches {
+		patches[i].start1 += patchMargin
+		patches[i].start2 += patchMargin
+	}
+
+	first := &patches[0]
+	if len(first.diffs) == 0 || first.diffs[0].op != diffmatchpatch.DiffEqual {
+		first.diffs = append([]diff{{diffmatchpatch.DiffEqual, append([]rune(nil), padding...)}}, first.diffs...)
+		first.start1 -= patchMargin
+		first.start2 -= patchMargin
+		first.length1 += patchMargin
+		first.length2 += patchMargin
+	} else if extra := patchMargin - len(first.diffs[0].text); extra > 0 {
+		first.diffs[0].text = append(append([]rune(nil), padding[len(first.diffs[0].text):]...), first.diffs[0].text...)
+		first.start1 -= extra
+		first.start2 -= extra
+		first.length1 += extra
+		first.length2 += extra
+	}
+
+	last := &patches[len(patches)-1]
+	if len(last.diffs) == 0 || last.diffs[len(last.diffs)-1].op != diffmatchpatch.DiffEqual {
+		last.diffs = append(last.diffs, diff{diffmatchpatch.DiffEqual, append([]rune(nil), padding...)})
+		last.length1 += patchMargin
+		last.length2 += patchMargin
+	} else if extra := patchMargin - len(last.diffs[len(last.diffs)-1].text); extra > 0 {
+		tail := &last.diffs[len(last.diffs)-1]
+		tail.text = append(tail.text, padding[:extra]...)
+		last.length1 += extra
+		last.length2 += extra
+	}
+	return padding
+}
+
+// splitMax breaks up the patches longer than the pattern length the matching supports.
+func splitMax(patches []patch) []patch {
+	var split []patch
+	for _, big := range patches {
+		if big.length1 <= matchMaxBits {
+			split = append(split, big)
+			continue
+		}
+
+		start1, start2 := big.start1, big.start2
+		var precontext []rune
+		for len(big.diffs) != 0 {
+			p := patch{start1: start1 - len(precontext), start2: start2 - len(precontext)}
+			empty := true
+			if len(precontext) != 0 {
+				p.length1, p.length2 = len(precontext), len(precontext)
+				p.diffs = append(p.diffs, diff{diffmatchpatch.DiffEqual, precontext})
+			}
+			for len(big.diffs) != 0 && p.length1 < matchMaxBits-patchMargin {
+				d := big.diffs[0]
+				switch {
+				case d.op == diffmatchpatch.DiffInsert:
+					// Insertions are harmless.
+					p.length2 += len(d.text)
+					start2 += len(d.text)
+					p.diffs = append(p.diffs, d)
+					big.diffs = big.diffs[1:]
+					empty = false
+				case d.op == diffmatchpatch.DiffDelete && len(p.diffs) == 1 &&
+					p.diffs[0].op == diffmatchpatch.DiffEqual && len(d.text) > 2*matchMaxBits:
+					// A large deletion, let it pass in one chunk.
+					p.length1 += len(d.text)
+					start1 += len(d.text)
+					p.diffs = append(p.diffs, d)
+					big.diffs = big.diffs[1:]
+					empty = false
+				default:
+					// A deletion or an equality, only take as much as fits.
+					text := d.text[:minInt(len(d.text), matchMaxBits-p.length1-patchMargin)]
+					p.length1 += len(text)
+					start1 += len(text)
+					if d.op == diffmatchpatch.DiffEqual {
+						p.length2 += len(text)
+						start2 += len(text)
+					} else {
+						empty = false
+					}
+					p.diffs = append(p.diffs, diff{d.op, text})
+					if len(text) == len(d.text) {
+						big.diffs = big.diffs[1:]
+					} else {
+						big.diffs[0].text = d.text[len(text):]
+					}
+				}
+			}
+
+			// The head context of the next patch.
+			precontext = diffText2(p.diffs)
+			precontext = precontext[maxInt(0, len(precontext)-patchMargin):]
+
+			// The tail context of this patch.
+			postcontext := diffText1(big.diffs)
+			postcontext = postcontext[:minInt(len(postcontext), patchMargin)]
+			if len(postcontext) != 0 {
+				p.length1 += len(postcontext)
+				p.length2 += len(postcontext)
+				if n := len(p.diffs); n != 0 && p.diffs[n-1].op == diffmatchpatch.DiffEqual {
+					p.diffs[n-1].text = append(append([]rune(nil), p.diffs[n-1].text...), postcontext...)
+				} else {
+					p.diffs = append(p.diffs, diff{diffmatchpatch.DiffEqual, postcontext})
+				}
+			}
+			if !empty {
+				split = append(split, p)
+			}
+		}
+	}
+	return split
+}
+
+// matchMain is match_main, it returns the location of the best match of the pattern near loc or -1.
+func matchMain(text, pattern []rune, loc int) int {
+	loc = maxInt(0, minInt(loc, len(text)))
+	switch {
+	case string(text) == string(pattern):
+		return 0
+	case len(text) == 0:
+		return -1
+	case loc+len(pattern) <= len(text) && string(text[loc:loc+len(pattern)]) == string(pattern):
+		return loc
+	}
+	return matchBitap(text, pattern, loc)
+}
+
+// matchBitap locates the best fuzzy match of the pattern near loc with the Bitap algorithm.
+func matchBitap(text, pattern []rune, loc int) int {
+	alphabet := make(map[rune]int)
+	for i, c := range pattern {
+		alphabet[c] |= 1 << uint(len(pattern)-i-1)
+	}
+	score := func(errors, x int) float64 {
+		return float64(errors)/float64(len(pattern)) + math.Abs(float64(loc-x))/matchDistance
+	}
+
+	threshold := matchThreshold
+	// Is there a nearby exact match?
+	if best := indexRunes(text, pattern, loc); best != -1 {
+		threshold = math.Min(score(0, best), threshold)
+		if best = lastIndexRunes(text, pattern, loc+len(pattern)); best != -1 {
+			threshold = math.Min(score(0, best), threshold)
+		}
+	}
+
+	matchmask := 1 << uint(len(pattern)-1)
+	bestLoc := -1
+	binMax := len(pattern) + len(text)
+	var lastRd []int
+	for d := 0; d < len(pattern); d++ {
+		// Each pass allows one more error, a binary search tells how far from loc the match may be.
+		binMin, binMid := 0, binMax
+		for binMin < binMid {
+			if score(d, loc+binMid) <= threshold {
+				binMin = binMid
+			} else {
+				binMax = binMid
+			}
+			binMid = (binMax-binMin)/2 + binMin
+		}
+		binMax = binMid
+		start := maxInt(1, loc-binMid+1)
+		finish := minInt(loc+binMid, len(text)) + len(pattern)
+
+		rd := make([]int, finish+2)
+		rd[finish+1] = (1 << uint(d)) - 1
+		for j := finish; j >= start; j-- {
+			var charMatch int
+			if j-1 < len(text) {
+				charMatch = alphabet[text[j-1]]
+			}
+			if d == 0 {
+				rd[j] = ((rd[j+1] << 1) | 1) & charMatch
+			} else {
+				rd[j] = (((rd[j+1] << 1) | 1) & charMatch) | (((lastRd[j+1] | lastRd[j]) << 1) | 1) | lastRd[j+1]
+			}
+			if rd[j]&matchmask != 0 {
+				if s := score(d, j-1); s <= threshold {
+					threshold = s
+					bestLoc = j - 1
+					if bestLoc <= loc {
+						// Already past loc, it only gets worse.
+						break
+					}
+					// Do not get further from loc than the current match.
+					start = maxInt(1, 2*loc-bestLoc)
+				}
+			}
+		}
+		if score(d+1, loc) > threshold {
+			// No better match is possible with more errors.
+			break
+		}
+		lastRd = rd
+	}
+	return bestLoc
+}
+
+func indexRunes(text, pattern []rune, from int) int {
+	for i := maxInt(0, from); i+len(pattern) <= len(text); i++ {
+		if string(text[i:i+len(pattern)]) == string(pattern) {
+			return i
+		}
+	}
+	return -1
+}
+
+func lastIndexRunes(text, pattern []rune, from int) int {
+	for i := minInt(from, len(text)-len(pattern)); i >= 0; i-- {
+		if string(text[i:i+len(pattern)]) == string(pattern) {
+			return i
+		}
+	}
+	return -1
+}
+
+// xIndex maps a location in the source text of the diffs to the location in the target text.
+func xIndex(diffs []diff, loc int) int {
+	chars1, chars2 := 0, 0
+	lastChars1, lastChars2 := 0, 0
+	var last *diff
+	for i := range diffs {
+		d := &diffs[i]
+		if d.op != diffmatchpatch.DiffInsert {
+			chars1 += len(d.text)
+		}
+		if d.op != diffmatchpatch.DiffDelete {
+			chars2 += len(d.text)
+		}
+		if chars1 > loc {
+			last = d
+			break
+		}
+		lastChars1, lastChars2 = chars1, chars2
+	}
+	if last != nil && last.op == diffmatchpatch.DiffDelete {
+		// The location was deleted.
+		return lastChars2
+	}
+	return lastChars2 + (loc - lastChars1)
+}
+
+func levenshtein(diffs []diff) int {
+	total, insertions, deletions := 0, 0, 0
+	for _, d := range diffs {
+		switch d.op {
+		case diffmatchpatch.DiffInsert:
+			insertions += len(d.text)
+		case diffmatchpatch.DiffDelete:
+			deletions += len(d.text)
+		default:
+			total += maxInt(insertions, deletions)
+			insertions, deletions = 0, 0
+		}
+	}
+	return total + maxInt(insertions, deletions)
+}
+
+func diffText1(diffs []diff) []rune {
+	var text []rune
+	for _, d := range diffs {
+		if d.op != diffmatchpatch.DiffInsert {
+			text = append(text, d.text...)
+		}
+	}
+	return text
+}
+
+func diffText2(diffs []diff) []rune {
+	var text []rune
+	for _, d := range diffs {
+		if d.op != diffmatchpatch.DiffDelete {
+			text = append(text, d.text...)
+		}
+	}
+	return text
+}
+
+// splice returns a copy of the text with n runes at index replaced by the insertion.
+func splice(text []rune, index, n int, insertion []rune) []rune {
+	spliced := make([]rune, 0, len(text)-n+len(insertion))
+	spliced = append(spliced, text[:index]...)
+	spliced = append(spliced, insertion...)
+	return append(spliced, text[index+n:]...)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
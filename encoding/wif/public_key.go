@@ -0,0 +1,61 @@
+package wif
+
+import (
+	// Stdlib
+	"bytes"
+	"strings"
+
+	// Vendor
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// PublicKeyPrefix is the prefix of the public keys in their text form,
+// change it to use the keys of a test network.
+var PublicKeyPrefix = "STM"
+
+// EncodePublicKey returns the text form of a 33-byte compressed public key:
+// the prefix followed by the base58 encoded key and its checksum.
+func EncodePublicKey(key []byte) (string, error) {
+	if _, err := btcec.ParsePubKey(key, btcec.S256()); err != nil {
+		return "", errors.Wrap(err, "invalid public key")
+	}
+	return PublicKeyPrefix + base58.Encode(append(append([]byte{}, key...), checksum(key)...)), nil
+}
+
+// DecodePublicKey turns the text form of a public key, e.g. STM6MRyAjQq8ud7hVNYcfnVPJqcVpscN5So8BhtHuGYqET5GDW5CV,
+// into the 33-byte compressed format.
+func DecodePublicKey(key string) ([]byte, error) {
+	if !strings.HasPrefix(key, PublicKeyPrefix) {
+		return nil, errors.Errorf("public key %q does not start with %v", key, PublicKeyPrefix)
+	}
+	data := base58.Decode(strings.TrimPrefix(key, PublicKeyPrefix))
+	if len(data) != 37 {
+		return nil, errors.Errorf("public key %q has an invalid length", key)
+	}
+	pub, sum := data[:33], data[33:]
+	if !bytes.Equal(sum, checksum(pub)) {
+		return nil, errors.Errorf("public key %q has an invalid checksum", key)
+	}
+	if _, err := btcec.ParsePubKey(pub, btcec.S256()); err != nil {
+		return nil, errors.Wrapf(err, "invalid public key %q", key)
+	}
+	return pub, nil
+}
+
+// GetPublicKeyString returns the text form of the public key associated with the given WIF.
+func GetPublicKeyString(wif string) (string, error) {
+	key, err := GetPublicKey(wif)
+	if err != nil {
+		return "", err
+	}
+	return EncodePublicKey(key)
+}
+
+func checksum(key []byte) []byte {
+	h := ripemd160.New()
+	h.Write(key)
+	return h.Sum(nil)[:4]
+}
@@ -0,0 +1,44 @@
+package wif
+
+import (
+	// Stdlib
+	"testing"
+)
+
+func TestPublicKey(t *testing.T) {
+	const (
+		privKey = "5KQwrPbwdL6PhXujxW37FSSQZ1JiwsST4cqQzDeyXtP79zkvFD3"
+		pubKey  = "STM6MRyAjQq8ud7hVNYcfnVPJqcVpscN5So8BhtHuGYqET5GDW5CV"
+	)
+
+	got, err := GetPublicKeyString(privKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != pubKey {
+		t.Errorf("expected %v, got %v", pubKey, got)
+	}
+
+	key, err := DecodePublicKey(pubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := GetPublicKey(privKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(key) != string(expected) {
+		t.Errorf("expected %x, got %x", expected, key)
+	}
+
+	for _, invalid := range []string{
+		"",
+		"GPH6MRyAjQq8ud7hVNYcfnVPJqcVpscN5So8BhtHuGYqET5GDW5CV",
+		"STM6MRyAjQq8ud7hVNYcfnVPJqcVpscN5So8BhtHuGYqET5GDW5CW",
+		"STM6MRyAjQq8ud7hVNYcfnVPJqcVpscN5So8BhtHuGYqET5G",
+	} {
+		if _, err := DecodePublicKey(invalid); err == nil {
+			t.Errorf("expected an error for %q", invalid)
+		}
+	}
+}
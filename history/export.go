@@ -0,0 +1,125 @@
+package history
+
+import (
+	// Stdlib
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	// Vendor
+	"github.com/pkg/errors"
+)
+
+// CSVHeader lists the columns written by the CSV encoder.
+// Multiple amounts are separated by a semicolon.
+var CSVHeader = []string{"sequence", "timestamp", "block", "trx_id", "op_type", "from", "to", "amounts", "memo"}
+
+// Encoder writes records in an export format.
+type Encoder interface {
+	Encode(record *Record) error
+	Flush() error
+}
+
+type csvEncoder struct {
+	w      *csv.Writer
+	header bool
+}
+
+// NewCSVEncoder returns an Encoder writing CSV. When header is set,
+// CSVHeader is written before the first record, leave it unset when appending to an existing export.
+func NewCSVEncoder(w io.Writer, header bool) Encoder {
+	return &csvEncoder{w: csv.NewWriter(w), header: header}
+}
+
+func (enc *csvEncoder) Encode(record *Record) error {
+	if enc.header {
+		if err := enc.w.Write(CSVHeader); err != nil {
+			return err
+		}
+		enc.header = false
+	}
+
+	var timestamp string
+	if !record.Timestamp.IsZero() {
+		timestamp = record.Timestamp.UTC().Format(time.RFC3339)
+	}
+	return enc.w.Write([]string{
+		strconv.FormatUint(record.Sequence, 10),
+		timestamp,
+		strconv.FormatUint(uint64(record.Block), 10),
+		record.TrxID,
+		string(record.OpType),
+		record.From,
+		record.To,
+		strings.Join(record.Amounts, ";"),
+		record.Memo,
+	})
+}
+
+func (enc *csvEncoder) Flush() error {
+	enc.w.Flush()
+	return enc.w.Error()
+}
+
+type jsonlEncoder struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+// NewJSONLEncoder returns an Encoder writing JSON Lines, one record per line.
+func NewJSONLEncoder(w io.Writer) Encoder {
+	bw := bufio.NewWriter(w)
+	return &jsonlEncoder{w: bw, enc: json.NewEncoder(bw)}
+}
+
+func (enc *jsonlEncoder) Encode(record *Record) error {
+	return enc.enc.Encode(record)
+}
+
+func (enc *jsonlEncoder) Flush() error {
+	return enc.w.Flush()
+}
+
+// LastCSVSequence returns the sequence number of the last record of a CSV export,
+// ok is false when the export contains no records.
+func LastCSVSequence(r io.Reader) (sequence uint64, ok bool, err error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(CSVHeader)
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return sequence, ok, nil
+		}
+		if err != nil {
+			return 0, false, errors.Wrap(err, "failed to read CSV export")
+		}
+		if row[0] == CSVHeader[0] {
+			continue
+		}
+		if sequence, err = strconv.ParseUint(row[0], 10, 64); err != nil {
+			return 0, false, errors.Wrapf(err, "invalid sequence number %q", row[0])
+		}
+		ok = true
+	}
+}
+
+// LastJSONLSequence returns the sequence number of the last record of a JSON Lines export,
+// ok is false when the export contains no records.
+func LastJSONLSequence(r io.Reader) (sequence uint64, ok bool, err error) {
+	dec := json.NewDecoder(r)
+	for {
+		var record Record
+		err := dec.Decode(&record)
+		if err == io.EOF {
+			return sequence, ok, nil
+		}
+		if err != nil {
+			return 0, false, errors.Wrap(err, "failed to read JSON Lines export")
+		}
+		sequence, ok = record.Sequence, true
+	}
+}
@@ -0,0 +1,239 @@
+// Package history walks the account history in chronological order
+// and exports it for accounting purposes.
+//
+// The history can be limited to a date or block range and filtered by
+// operation type. Every operation is reported once, even though the pages
+// returned by get_account_history overlap, and a walk can be resumed
+// after the last exported sequence number:
+//
+//	w := history.New(client.Database, "alice",
+//		history.SetOpTypes(types.TypeTransfer, types.TypeClaimRewardBalance),
+//		history.ResumeAfter(last),
+//	)
+//	n, last, err := w.Export(history.NewCSVEncoder(file, false))
+package history
+
+import (
+	// Stdlib
+	"time"
+
+	// Vendor
+	"github.com/pkg/errors"
+
+	// RPC
+	"github.com/asuleymanov/rpc/apis/database"
+	"github.com/asuleymanov/rpc/types"
+)
+
+// DefaultPageSize is the number of operations requested at once.
+const DefaultPageSize = 1000
+
+// MaxPageSize is the maximum page size accepted by steemd.
+const MaxPageSize = 10000
+
+// Option configures a Walker.
+type Option func(*Walker)
+
+// SetOpTypes limits the walk to the given operation types.
+func SetOpTypes(kinds ...types.OpType) Option {
+	return func(w *Walker) {
+		w.opTypes = make(map[types.OpType]bool, len(kinds))
+		for _, kind := range kinds {
+			w.opTypes[kind] = true
+		}
+	}
+}
+
+// SetTimeRange limits the walk to the operations in [from, to).
+// A zero time leaves the bound open.
+func SetTimeRange(from, to time.Time) Option {
+	return func(w *Walker) {
+		w.fromTime, w.toTime = from, to
+	}
+}
+
+// SetBlockRange limits the walk to the operations in blocks [from, to].
+// Zero leaves the bound open.
+func SetBlockRange(from, to uint32) Option {
+	return func(w *Walker) {
+		w.fromBlock, w.toBlock = from, to
+	}
+}
+
+// ResumeAfter starts the walk after the operation with the given sequence number,
+// usually the last sequence number returned by Export.
+func ResumeAfter(sequence uint64) Option {
+	return func(w *Walker) {
+		w.start = sequence + 1
+		w.resume = true
+	}
+}
+
+// SetPageSize sets the number of operations requested at once.
+func SetPageSize(size uint32) Option {
+	return func(w *Walker) {
+		if size > 0 && size <= MaxPageSize {
+			w.pageSize = size
+		}
+	}
+}
+
+// Walker walks the history of a single account, the oldest operation first.
+type Walker struct {
+	api     *database.API
+	account string
+
+	opTypes   map[types.OpType]bool
+	fromTime  time.Time
+	toTime    time.Time
+	fromBlock uint32
+	toBlock   uint32
+	start     uint64
+	resume    bool
+	pageSize  uint32
+}
+
+// New creates a Walker for the history of account.
+func New(api *database.API, account string, options ...Option) *Walker {
+	w := &Walker{
+		api:      api,
+		account:  account,
+		pageSize: DefaultPageSize,
+	}
+	for _, opt := range options {
+		opt(w)
+	}
+	return w
+}
+
+// Walk calls fn for every matching operation in chronological order.
+// The walk stops at the operations existing when it started, or on the first error returned by fn.
+func (w *Walker) Walk(fn func(*database.AccountHistoryEntry) error) error {
+	head, ok, err := w.head()
+	if err != nil || !ok {
+		return err
+	}
+
+	start := w.start
+	if !w.resume && (w.fromBlock != 0 || !w.fromTime.IsZero()) {
+		if start, err = w.seek(head); err != nil {
+			return err
+		}
+	}
+
+	var (
+		last uint64
+		seen bool
+	)
+	for start <= head {
+		to := start + uint64(w.pageSize) - 1
+		if to > head {
+			to = head
+		}
+		entries, err := w.api.GetAccountHistoryEntries(w.account, int64(to), uint32(to-start))
+		if err != nil {
+			return errors.Wrapf(err, "failed to get history of %v at %v", w.account, to)
+		}
+
+		for _, entry := range entries {
+			if entry.Sequence < start || entry.Sequence > to || (seen && entry.Sequence <= last) {
+				continue
+			}
+			last, seen = entry.Sequence, true
+
+			if w.after(entry) {
+				return nil
+			}
+			if !w.match(entry) {
+				continue
+			}
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+		start = to + 1
+	}
+	return nil
+}
+
+// head returns the sequence number of the latest operation of the account.
+func (w *Walker) head() (uint64, bool, error) {
+	entries, err := w.api.GetAccountHistoryEntries(w.account, -1, 0)
+	if err != nil {
+		return 0, false, errors.Wrapf(err, "failed to get history of %v", w.account)
+	}
+	if len(entries) == 0 {
+		return 0, false, nil
+	}
+	return entries[len(entries)-1].Sequence, true, nil
+}
+
+// seek returns the sequence number of the first operation not before the lower bounds,
+// using a binary search over the sequence numbers.
+func (w *Walker) seek(head uint64) (uint64, error) {
+	lo, hi := uint64(0), head+1
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		entries, err := w.api.GetAccountHistoryEntries(w.account, int64(mid), 0)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to get history of %v at %v", w.account, mid)
+		}
+		if len(entries) == 0 {
+			return 0, errors.Errorf("history of %v is missing operation %v", w.account, mid)
+		}
+		if w.before(entries[len(entries)-1]) {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo, nil
+}
+
+// before reports whether the entry precedes the lower bounds.
+func (w *Walker) before(entry *database.AccountHistoryEntry) bool {
+	op := entry.Operation
+	if op == nil {
+		return false
+	}
+	if w.fromBlock != 0 && op.BlockNumber < w.fromBlock {
+		return true
+	}
+	return !w.fromTime.IsZero() && op.Timestamp != nil && op.Timestamp.Time != nil && op.Timestamp.Before(w.fromTime)
+}
+
+// after reports whether the entry follows the upper bounds.
+func (w *Walker) after(entry *database.AccountHistoryEntry) bool {
+	op := entry.Operation
+	if op == nil {
+		return false
+	}
+	if w.toBlock != 0 && op.BlockNumber > w.toBlock {
+		return true
+	}
+	return !w.toTime.IsZero() && op.Timestamp != nil && op.Timestamp.Time != nil && !op.Timestamp.Before(w.toTime)
+}
+
+func (w *Walker) match(entry *database.AccountHistoryEntry) bool {
+	if entry.Operation == nil || w.before(entry) {
+		return false
+	}
+	return w.opTypes == nil || w.opTypes[entry.Operation.OperationType]
+}
+
+// Export encodes every matching operation using enc. It returns the number of
+// exported operations and the sequence number of the last one, to be passed
+// to ResumeAfter the next time.
+func (w *Walker) Export(enc Encoder) (n int, last uint64, err error) {
+	err = w.Walk(func(entry *database.AccountHistoryEntry) error {
+		if err := enc.Encode(NewRecord(entry)); err != nil {
+			return err
+		}
+		n, last = n+1, entry.Sequence
+		return nil
+	})
+	if ferr := enc.Flush(); err == nil {
+		err = ferr
+	}
+	return n, last, err
+}
@@ -0,0 +1,149 @@
+package history
+
+import (
+	// Stdlib
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	// RPC
+	"github.com/asuleymanov/rpc/apis/database"
+	"github.com/asuleymanov/rpc/types"
+)
+
+var genesis = time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// fakeNode serves a history of transfers and votes alternating,
+// operation i is included in block 100+i, i minutes after genesis.
+type fakeNode struct {
+	size  int
+	calls int
+}
+
+func (node *fakeNode) Call(method string, params, response interface{}) error {
+	if method != "get_account_history" {
+		return fmt.Errorf("unexpected method %v", method)
+	}
+	node.calls++
+	args := params.([]interface{})
+	from, limit := args[1].(int64), int64(args[2].(uint32))
+	if from < 0 || from >= int64(node.size) {
+		from = int64(node.size) - 1
+	}
+
+	var page [][]interface{}
+	for seq := from - limit; seq <= from; seq++ {
+		if seq >= 0 {
+			page = append(page, []interface{}{seq, node.operation(seq)})
+		}
+	}
+	data, err := json.Marshal(page)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, response)
+}
+
+func (node *fakeNode) operation(seq int64) map[string]interface{} {
+	op := []interface{}{"vote", map[string]interface{}{"voter": "alice", "author": "bob", "permlink": "post"}}
+	if seq%2 == 0 {
+		op = []interface{}{"transfer", map[string]interface{}{
+			"from":   "alice",
+			"to":     "bob",
+			"amount": fmt.Sprintf("%d.000 STEEM", seq),
+			"memo":   "rent",
+		}}
+	}
+	return map[string]interface{}{
+		"block":     100 + seq,
+		"trx_id":    fmt.Sprintf("trx%d", seq),
+		"op":        op,
+		"timestamp": genesis.Add(time.Duration(seq) * time.Minute).Format("2006-01-02T15:04:05"),
+	}
+}
+
+func sequences(t *testing.T, w *Walker) []uint64 {
+	var seqs []uint64
+	err := w.Walk(func(entry *database.AccountHistoryEntry) error {
+		seqs = append(seqs, entry.Sequence)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return seqs
+}
+
+func TestWalk(t *testing.T) {
+	api := database.NewAPI(&fakeNode{size: 25})
+
+	seqs := sequences(t, New(api, "alice", SetPageSize(10)))
+	if len(seqs) != 25 {
+		t.Fatalf("expected 25 operations, got %v", len(seqs))
+	}
+	for i, seq := range seqs {
+		if seq != uint64(i) {
+			t.Fatalf("expected sequence %v at %v, got %v", i, i, seq)
+		}
+	}
+
+	seqs = sequences(t, New(api, "alice",
+		SetPageSize(4),
+		SetOpTypes(types.TypeTransfer),
+		SetTimeRange(genesis.Add(5*time.Minute), genesis.Add(15*time.Minute)),
+	))
+	if got := fmt.Sprint(seqs); got != "[6 8 10 12 14]" {
+		t.Errorf("unexpected filtered sequences %v", got)
+	}
+
+	seqs = sequences(t, New(api, "alice", SetBlockRange(120, 0)))
+	if got := fmt.Sprint(seqs); got != "[20 21 22 23 24]" {
+		t.Errorf("unexpected block range sequences %v", got)
+	}
+}
+
+func TestExportResume(t *testing.T) {
+	node := &fakeNode{size: 3}
+	api := database.NewAPI(node)
+
+	var buf bytes.Buffer
+	n, last, err := New(api, "alice", SetOpTypes(types.TypeTransfer)).Export(NewCSVEncoder(&buf, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || last != 2 {
+		t.Errorf("expected 2 records up to 2, got %v up to %v", n, last)
+	}
+
+	expected := strings.Join([]string{
+		"sequence,timestamp,block,trx_id,op_type,from,to,amounts,memo",
+		"0,2018-01-01T00:00:00Z,100,trx0,transfer,alice,bob,0.000 STEEM,rent",
+		"2,2018-01-01T00:02:00Z,102,trx2,transfer,alice,bob,2.000 STEEM,rent",
+		"",
+	}, "\n")
+	if buf.String() != expected {
+		t.Errorf("unexpected export:\n%v", buf.String())
+	}
+
+	seq, ok, err := LastCSVSequence(strings.NewReader(buf.String()))
+	if err != nil || !ok || seq != 2 {
+		t.Fatalf("expected last sequence 2, got %v %v %v", seq, ok, err)
+	}
+
+	// More operations arrive, only the new ones are exported.
+	node.size = 6
+	buf.Reset()
+	n, last, err = New(api, "alice", ResumeAfter(seq)).Export(NewJSONLEncoder(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 || last != 5 {
+		t.Errorf("expected 3 records up to 5, got %v up to %v", n, last)
+	}
+	if seq, ok, err := LastJSONLSequence(&buf); err != nil || !ok || seq != 5 {
+		t.Errorf("expected last sequence 5, got %v %v %v", seq, ok, err)
+	}
+}
@@ -0,0 +1,108 @@
+package history
+
+import (
+	// Stdlib
+	"time"
+
+	// RPC
+	"github.com/asuleymanov/rpc/apis/database"
+	"github.com/asuleymanov/rpc/types"
+)
+
+// Record is the normalized form of an operation used for the exports.
+//
+// From is the account the value moves from, or the one acting, To the account
+// the value moves to, or the one affected. Amounts are kept as returned by steemd,
+// e.g. "1.000 STEEM".
+type Record struct {
+	Sequence  uint64       `json:"sequence"`
+	Timestamp time.Time    `json:"timestamp"`
+	Block     uint32       `json:"block"`
+	TrxID     string       `json:"trx_id"`
+	OpType    types.OpType `json:"op_type"`
+	From      string       `json:"from,omitempty"`
+	To        string       `json:"to,omitempty"`
+	Amounts   []string     `json:"amounts,omitempty"`
+	Memo      string       `json:"memo,omitempty"`
+}
+
+// NewRecord converts a history entry into a Record.
+func NewRecord(entry *database.AccountHistoryEntry) *Record {
+	record := &Record{Sequence: entry.Sequence}
+	obj := entry.Operation
+	if obj == nil {
+		return record
+	}
+
+	record.Block = obj.BlockNumber
+	record.TrxID = obj.TransactionID
+	record.OpType = obj.OperationType
+	if obj.Timestamp != nil && obj.Timestamp.Time != nil {
+		record.Timestamp = obj.Timestamp.UTC()
+	}
+
+	switch op := obj.Operation.(type) {
+	case *types.TransferOperation:
+		record.set(op.From, op.To, op.Memo, op.Amount)
+	case *types.TransferToVestingOperation:
+		record.set(op.From, op.To, "", op.Amount)
+	case *types.TransferToSavingsOperation:
+		record.set(op.From, op.To, op.Memo, op.Amount)
+	case *types.TransferFromSavingsOperation:
+		record.set(op.From, op.To, op.Memo, op.Amount)
+	case *types.FillTransferFromSavingsOperation:
+		record.set(op.From, op.To, op.Memo, op.Amount)
+	case *types.WithdrawVestingOperation:
+		record.set(op.Account, "", "", op.VestingShares)
+	case *types.FillVestingWithdrawOperation:
+		record.set(op.FromAccount, op.ToAccount, "", op.Withdrawn, op.Deposited)
+	case *types.DelegateVestingSharesOperation:
+		record.set(op.Delegator, op.Delegatee, "", op.VestingShares)
+	case *types.ReturnVestingDelegationOperation:
+		record.set("", op.Account, "", op.VestingShares)
+	case *types.ConvertOperation:
+		record.set(op.Owner, "", "", op.Amount)
+	case *types.FillConvertRequestOperation:
+		record.set(op.Owner, "", "", op.AmountIn, op.AmountOut)
+	case *types.LimitOrderCreateOperation:
+		record.set(op.Owner, "", "", op.AmountToSell, op.MinToReceive)
+	case *types.FillOrderOperation:
+		record.set(op.CurrentOwner, op.OpenOwner, "", op.CurrentPays, op.OpenPays)
+	case *types.ClaimRewardBalanceOperation:
+		record.set("", op.Account, "", op.RewardSteem, op.RewardSbd, op.RewardVests)
+	case *types.AuthorRewardOperation:
+		record.set("", op.Author, "", op.SbdPayout, op.SteemPayout, op.VestingPayout)
+	case *types.CurationRewardOperation:
+		record.set(op.CommentAuthor, op.Curator, "", op.Reward)
+	case *types.CommentBenefactorRewardOperation:
+		record.set(op.Author, op.Benefactor, "", op.Reward)
+	case *types.CommentRewardOperation:
+		record.set("", op.Author, "", op.Payout)
+	case *types.LiquidityRewardOperation:
+		record.set("", op.Owner, "", op.Payout)
+	case *types.InterestOperation:
+		record.set("", op.Owner, "", op.Interest)
+	case *types.EscrowTransferOperation:
+		record.set(op.From, op.To, "", op.SbdAmount, op.SteemAmount, op.Fee)
+	case *types.EscrowReleaseOperation:
+		record.set(op.From, op.Receiver, "", op.SbdAmount, op.SteemAmount)
+	case *types.AccountCreateOperation:
+		record.set(op.Creator, op.NewAccountName, "", op.Fee)
+	case *types.AccountCreateWithDelegationOperation:
+		record.set(op.Creator, op.NewAccountName, "", op.Fee, op.Delegation)
+	case *types.VoteOperation:
+		record.set(op.Voter, op.Author, "")
+	case *types.CommentOperation:
+		record.set(op.Author, op.ParentAuthor, "")
+	}
+	return record
+}
+
+func (record *Record) set(from, to, memo string, amounts ...string) {
+	record.From, record.To, record.Memo = from, to, memo
+	for _, amount := range amounts {
+		if amount != "" {
+			record.Amounts = append(record.Amounts, amount)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+package interfaces
+
+// ConnectionCounter is implemented by transports that can reconnect,
+// possibly to a different node with a different set of APIs enabled.
+type ConnectionCounter interface {
+	// Connections returns the number of connections established so far.
+	Connections() uint64
+}
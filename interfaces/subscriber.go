@@ -0,0 +1,14 @@
+package interfaces
+
+import "encoding/json"
+
+// Subscriber is implemented by transports supporting server-initiated notifications,
+// e.g. the callbacks registered using set_block_applied_callback.
+type Subscriber interface {
+	// Subscribe allocates a new callback ID and performs the call returned by params
+	// to register it. Every notice sent for the callback is passed into notify.
+	// The subscription is renewed automatically every time the transport reconnects.
+	//
+	// Calling the returned function stops delivering notices for the callback.
+	Subscribe(method string, params func(callbackID uint32) interface{}, notify func(payload json.RawMessage)) (unsubscribe func(), err error)
+}
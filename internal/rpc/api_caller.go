@@ -0,0 +1,63 @@
+package rpc
+
+import (
+	// Stdlib
+	"sync"
+
+	// RPC
+	"github.com/asuleymanov/rpc/interfaces"
+)
+
+// APICaller calls methods of a single API using the "call" method.
+//
+// The numeric API ID is resolved lazily on the first call and cached.
+// In case the underlying caller implements interfaces.ConnectionCounter,
+// the ID is resolved again after every reconnect.
+type APICaller struct {
+	caller  interfaces.Caller
+	apiName string
+
+	mu          sync.Mutex
+	id          int
+	resolved    bool
+	connections uint64
+}
+
+// NewAPICaller creates a new APICaller for the given API, no call is performed yet.
+func NewAPICaller(caller interfaces.Caller, apiName string) *APICaller {
+	return &APICaller{caller: caller, apiName: apiName}
+}
+
+// ID returns the numeric ID of the API, resolving it when necessary.
+// ErrAPIUnavailable is returned when the node does not expose the API.
+func (api *APICaller) ID() (int, error) {
+	var connections uint64
+	if counter, ok := api.caller.(interfaces.ConnectionCounter); ok {
+		connections = counter.Connections()
+	}
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	if api.resolved && api.connections == connections {
+		return api.id, nil
+	}
+
+	id, err := GetNumericAPIID(api.caller, api.apiName)
+	if err != nil {
+		return 0, err
+	}
+	api.id = id
+	api.resolved = true
+	api.connections = connections
+	return id, nil
+}
+
+// Call calls the given method of the API.
+func (api *APICaller) Call(method string, params, response interface{}) error {
+	id, err := api.ID()
+	if err != nil {
+		return err
+	}
+	return api.caller.Call("call", []interface{}{id, method, params}, response)
+}
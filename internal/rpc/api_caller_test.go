@@ -0,0 +1,72 @@
+package rpc
+
+import (
+	// Stdlib
+	"encoding/json"
+	"testing"
+
+	// Vendor
+	"github.com/pkg/errors"
+)
+
+// fakeNode exposes follow_api only, with an ID changing on every connection.
+type fakeNode struct {
+	connections uint64
+	lookups     int
+	lastAPI     interface{}
+}
+
+func (node *fakeNode) Connections() uint64 {
+	return node.connections
+}
+
+func (node *fakeNode) Call(method string, params, response interface{}) error {
+	args := params.([]interface{})
+	if args[1] != "get_api_by_name" {
+		node.lastAPI = args[0]
+		return nil
+	}
+
+	node.lookups++
+	var result interface{}
+	if args[2].([]interface{})[0] == "follow_api" {
+		result = 10 + node.connections
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, response)
+}
+
+func TestAPICaller(t *testing.T) {
+	node := &fakeNode{connections: 1}
+
+	follow := NewAPICaller(node, "follow_api")
+	if node.lookups != 0 {
+		t.Fatal("API resolved eagerly")
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := follow.Call("get_followers", nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if node.lookups != 1 || node.lastAPI != 11 {
+		t.Errorf("expected a single lookup and API 11, got %v lookups and API %v", node.lookups, node.lastAPI)
+	}
+
+	// Reconnect, the ID must be resolved again.
+	node.connections++
+	if err := follow.Call("get_followers", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if node.lookups != 2 || node.lastAPI != 12 {
+		t.Errorf("expected a second lookup and API 12, got %v lookups and API %v", node.lookups, node.lastAPI)
+	}
+
+	market := NewAPICaller(node, "market_history_api")
+	if err := market.Call("get_ticker", nil, nil); errors.Cause(err) != ErrAPIUnavailable {
+		t.Errorf("expected ErrAPIUnavailable, got %v", err)
+	}
+}
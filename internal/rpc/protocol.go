@@ -0,0 +1,123 @@
+package rpc
+
+import (
+	// Stdlib
+	"encoding/json"
+	"strings"
+	"sync"
+
+	// RPC
+	"github.com/asuleymanov/rpc/interfaces"
+)
+
+// CondenserAPI is the appbase API serving the legacy methods.
+const CondenserAPI = "condenser_api"
+
+// AppbaseCaller translates legacy calls into appbase calls.
+//
+// Calls sent as "call" [apiID, method, params] as well as direct database_api calls
+// are sent to condenser_api.<method> with the same positional parameters.
+// get_api_by_name is answered locally, since appbase nodes do not use numeric API IDs.
+// Dotted method names, e.g. block_api.get_block_range, are passed through unchanged.
+type AppbaseCaller struct {
+	next interfaces.Caller
+
+	mu  sync.Mutex
+	ids map[string]int
+}
+
+// NewAppbaseCaller creates a new AppbaseCaller sending the translated calls to next.
+func NewAppbaseCaller(next interfaces.Caller) *AppbaseCaller {
+	return &AppbaseCaller{next: next, ids: make(map[string]int)}
+}
+
+// Call implements interfaces.Caller.
+func (caller *AppbaseCaller) Call(method string, params, response interface{}) error {
+	if strings.Contains(method, ".") {
+		return caller.next.Call(method, params, response)
+	}
+	if method != "call" {
+		return caller.next.Call(CondenserAPI+"."+method, params, response)
+	}
+
+	args, ok := params.([]interface{})
+	if !ok || len(args) != 3 {
+		return caller.next.Call(method, params, response)
+	}
+	name, ok := args[1].(string)
+	if !ok {
+		return caller.next.Call(method, params, response)
+	}
+
+	if name == "get_api_by_name" {
+		apiArgs, ok := args[2].([]interface{})
+		if !ok || len(apiArgs) != 1 {
+			return caller.next.Call(CondenserAPI+"."+name, args[2], response)
+		}
+		apiName, _ := apiArgs[0].(string)
+		return encode(caller.apiID(apiName), response)
+	}
+
+	return caller.next.Call(CondenserAPI+"."+name, args[2], response)
+}
+
+// apiID returns a stable fake numeric ID for the given API name.
+func (caller *AppbaseCaller) apiID(apiName string) int {
+	caller.mu.Lock()
+	defer caller.mu.Unlock()
+
+	id, ok := caller.ids[apiName]
+	if !ok {
+		// IDs 0 and 1 are reserved for database_api and login_api.
+		id = len(caller.ids) + 2
+		caller.ids[apiName] = id
+	}
+	return id
+}
+
+// LegacyCaller translates dotted appbase method names into legacy calls,
+// e.g. block_api.get_block_range becomes "call" [blockAPIID, "get_block_range", params].
+//
+// The numeric API IDs are resolved lazily using APICaller, so calling an API
+// the node does not expose fails with ErrAPIUnavailable.
+type LegacyCaller struct {
+	next interfaces.Caller
+
+	mu   sync.Mutex
+	apis map[string]*APICaller
+}
+
+// NewLegacyCaller creates a new LegacyCaller sending the translated calls to next.
+func NewLegacyCaller(next interfaces.Caller) *LegacyCaller {
+	return &LegacyCaller{next: next, apis: make(map[string]*APICaller)}
+}
+
+// Call implements interfaces.Caller.
+func (caller *LegacyCaller) Call(method string, params, response interface{}) error {
+	i := strings.Index(method, ".")
+	if i == -1 {
+		return caller.next.Call(method, params, response)
+	}
+	apiName, name := method[:i], method[i+1:]
+
+	caller.mu.Lock()
+	api, ok := caller.apis[apiName]
+	if !ok {
+		api = NewAPICaller(caller.next, apiName)
+		caller.apis[apiName] = api
+	}
+	caller.mu.Unlock()
+
+	return api.Call(name, []interface{}{params}, response)
+}
+
+func encode(v interface{}, response interface{}) error {
+	if response == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, response)
+}
@@ -0,0 +1,80 @@
+package rpc
+
+import (
+	// Stdlib
+	"encoding/json"
+	"testing"
+
+	// Vendor
+	"github.com/pkg/errors"
+)
+
+type recordingCaller struct {
+	method string
+	params string
+}
+
+func (caller *recordingCaller) Call(method string, params, response interface{}) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	caller.method, caller.params = method, string(data)
+	return nil
+}
+
+func TestAppbaseCaller(t *testing.T) {
+	next := &recordingCaller{}
+	caller := NewAppbaseCaller(next)
+
+	cases := []struct {
+		method         string
+		params         interface{}
+		expectedMethod string
+		expectedParams string
+	}{
+		{"get_accounts", [][]string{{"alice"}}, "condenser_api.get_accounts", `[["alice"]]`},
+		{"call", []interface{}{3, "get_followers", []interface{}{"alice", "", "blog", 10}},
+			"condenser_api.get_followers", `["alice","","blog",10]`},
+		{"block_api.get_block_range", map[string]interface{}{"count": 1},
+			"block_api.get_block_range", `{"count":1}`},
+	}
+	for _, c := range cases {
+		if err := caller.Call(c.method, c.params, nil); err != nil {
+			t.Fatal(err)
+		}
+		if next.method != c.expectedMethod || next.params != c.expectedParams {
+			t.Errorf("%v: expected %v %v, got %v %v", c.method, c.expectedMethod, c.expectedParams, next.method, next.params)
+		}
+	}
+
+	// get_api_by_name is answered locally with stable IDs.
+	next.method = ""
+	followID, err := GetNumericAPIID(caller, "follow_api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.method != "" {
+		t.Errorf("get_api_by_name sent to the node as %v", next.method)
+	}
+	if id, _ := GetNumericAPIID(caller, "follow_api"); id != followID {
+		t.Errorf("expected the same ID %v, got %v", followID, id)
+	}
+}
+
+func TestLegacyCaller(t *testing.T) {
+	node := &fakeNode{connections: 1}
+	caller := NewLegacyCaller(node)
+
+	if err := caller.Call("follow_api.get_followers", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if node.lastAPI != 11 {
+		t.Errorf("expected API 11, got %v", node.lastAPI)
+	}
+
+	err := caller.Call("block_api.get_block_range", nil, nil)
+	if errors.Cause(err) != ErrAPIUnavailable {
+		t.Errorf("expected ErrAPIUnavailable, got %v", err)
+	}
+}
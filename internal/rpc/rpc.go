@@ -11,6 +11,9 @@ import (
 	"github.com/pkg/errors"
 )
 
+// ErrAPIUnavailable is returned when the node does not expose the requested API.
+var ErrAPIUnavailable = errors.New("API not available")
+
 func GetNumericAPIID(caller interfaces.Caller, apiName string) (int, error) {
 	params := []interface{}{apiName}
 	var resp json.RawMessage
@@ -18,7 +21,7 @@ func GetNumericAPIID(caller interfaces.Caller, apiName string) (int, error) {
 		return 0, err
 	}
 	if string(resp) == "null" {
-		return 0, errors.Errorf("API not available: %v", apiName)
+		return 0, errors.Wrap(ErrAPIUnavailable, apiName)
 	}
 	var id int
 	if err := json.Unmarshal([]byte(resp), &id); err != nil {
@@ -0,0 +1,175 @@
+package iterator
+
+import (
+	// RPC
+	"github.com/asuleymanov/rpc/apis/database"
+)
+
+// Page sizes used for the database_api endpoints, the maximum values accepted by steemd.
+const (
+	HistoryPageSize    = 1000
+	AccountPageSize    = 1000
+	WitnessPageSize    = 100
+	DiscussionPageSize = 100
+)
+
+// HistoryIterator iterates over the account history, the newest operation first.
+type HistoryIterator struct {
+	*pager
+}
+
+// Value returns the current history entry.
+func (it *HistoryIterator) Value() *database.AccountHistoryEntry {
+	return it.value.(*database.AccountHistoryEntry)
+}
+
+// AccountHistory iterates over the history of the account backwards,
+// starting with the most recent operation.
+func AccountHistory(api *database.API, account string) *HistoryIterator {
+	var (
+		from  int64  = -1
+		limit uint32 = HistoryPageSize
+	)
+	return &HistoryIterator{newPager(func() ([]interface{}, bool, error) {
+		entries, err := api.GetAccountHistoryEntries(account, from, limit)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(entries) == 0 {
+			return nil, true, nil
+		}
+
+		// The entries are returned in ascending order.
+		var items []interface{}
+		for i := len(entries) - 1; i >= 0; i-- {
+			if from >= 0 && int64(entries[i].Sequence) > from {
+				continue
+			}
+			items = append(items, entries[i])
+		}
+
+		oldest := int64(entries[0].Sequence)
+		if oldest == 0 || len(items) == 0 {
+			return items, true, nil
+		}
+		from = oldest - 1
+		if from < int64(limit) {
+			// steemd requires the limit not to exceed the start.
+			limit = uint32(from)
+		}
+		return items, false, nil
+	})}
+}
+
+// AccountIterator iterates over account names.
+type AccountIterator struct {
+	*pager
+}
+
+// Value returns the current account name.
+func (it *AccountIterator) Value() string {
+	return it.value.(string)
+}
+
+// LookupAccounts iterates over all the account names
+// in alphabetical order, starting with lowerBound.
+func LookupAccounts(api *database.API, lowerBound string) *AccountIterator {
+	start, first := lowerBound, true
+	return &AccountIterator{newPager(func() ([]interface{}, bool, error) {
+		names, err := api.LookupAccounts(start, AccountPageSize)
+		if err != nil {
+			return nil, false, err
+		}
+
+		var items []interface{}
+		for _, name := range names {
+			if !first && name == start {
+				continue
+			}
+			items = append(items, name)
+		}
+		first = false
+		if len(items) == 0 {
+			return nil, true, nil
+		}
+		start = names[len(names)-1]
+		return items, len(names) < AccountPageSize, nil
+	})}
+}
+
+// WitnessIterator iterates over witnesses.
+type WitnessIterator struct {
+	*pager
+}
+
+// Value returns the current witness.
+func (it *WitnessIterator) Value() *database.Witness {
+	return it.value.(*database.Witness)
+}
+
+// WitnessesByVote iterates over all the witnesses ordered by votes.
+func WitnessesByVote(api *database.API) *WitnessIterator {
+	var start string
+	return &WitnessIterator{newPager(func() ([]interface{}, bool, error) {
+		witnesses, err := api.GetWitnessByVote(start, WitnessPageSize)
+		if err != nil {
+			return nil, false, err
+		}
+
+		var items []interface{}
+		for _, witness := range witnesses {
+			if start != "" && witness.Owner == start {
+				continue
+			}
+			items = append(items, witness)
+		}
+		if len(items) == 0 {
+			return nil, true, nil
+		}
+		start = witnesses[len(witnesses)-1].Owner
+		return items, len(witnesses) < WitnessPageSize, nil
+	})}
+}
+
+// DiscussionIterator iterates over discussions.
+type DiscussionIterator struct {
+	*pager
+}
+
+// Value returns the current discussion.
+func (it *DiscussionIterator) Value() *database.Content {
+	return it.value.(*database.Content)
+}
+
+// Discussions iterates over the discussions returned by any of the GetDiscussionsBy* methods,
+// e.g.
+//
+//	it := iterator.Discussions(client.Database.GetDiscussionsByCreated, &database.DiscussionQuery{Tag: "golang"})
+//
+// The query is copied, the Limit field is ignored.
+func Discussions(fetch func(*database.DiscussionQuery) ([]*database.Content, error), query *database.DiscussionQuery) *DiscussionIterator {
+	q := *query
+	q.Limit = DiscussionPageSize
+	first := true
+	return &DiscussionIterator{newPager(func() ([]interface{}, bool, error) {
+		contents, err := fetch(&q)
+		if err != nil {
+			return nil, false, err
+		}
+
+		var items []interface{}
+		for _, content := range contents {
+			if !first && content.Author == q.StartAuthor && content.Permlink == q.StartPermlink {
+				continue
+			}
+			items = append(items, content)
+		}
+		first = false
+		if len(items) == 0 {
+			return nil, true, nil
+		}
+		last := contents[len(contents)-1]
+		q.StartAuthor, q.StartPermlink = last.Author, last.Permlink
+		return items, len(contents) < DiscussionPageSize, nil
+	})}
+}
@@ -0,0 +1,194 @@
+package iterator
+
+import (
+	// RPC
+	"github.com/asuleymanov/rpc/apis/follow"
+	"github.com/asuleymanov/rpc/types"
+)
+
+// Page sizes used for the follow_api endpoints, the maximum values accepted by steemd.
+const (
+	FollowPageSize     = 1000
+	ReputationPageSize = 1000
+	BlogPageSize       = 500
+)
+
+// FollowIterator iterates over follow objects.
+type FollowIterator struct {
+	*pager
+}
+
+// Value returns the current follow object.
+func (it *FollowIterator) Value() *follow.FollowObject {
+	return it.value.(*follow.FollowObject)
+}
+
+// Followers iterates over the followers of the account, kind being e.g. "blog" or "ignore".
+func Followers(api *follow.API, account, kind string) *FollowIterator {
+	return follows(api.GetFollowers, account, kind, func(obj *follow.FollowObject) string {
+		return obj.Follower
+	})
+}
+
+// Following iterates over the accounts followed by the account, kind being e.g. "blog" or "ignore".
+func Following(api *follow.API, account, kind string) *FollowIterator {
+	return follows(api.GetFollowing, account, kind, func(obj *follow.FollowObject) string {
+		return obj.Following
+	})
+}
+
+func follows(
+	get func(account, start, kind string, limit uint16) ([]*follow.FollowObject, error),
+	account string,
+	kind string,
+	key func(*follow.FollowObject) string,
+) *FollowIterator {
+
+	var start string
+	return &FollowIterator{newPager(func() ([]interface{}, bool, error) {
+		objs, err := get(account, start, kind, FollowPageSize)
+		if err != nil {
+			return nil, false, err
+		}
+
+		var items []interface{}
+		for _, obj := range objs {
+			// The page starts with the cursor, skip it.
+			if start != "" && key(obj) == start {
+				continue
+			}
+			items = append(items, obj)
+		}
+		if len(items) == 0 {
+			return nil, true, nil
+		}
+		start = key(objs[len(objs)-1])
+		return items, len(objs) < FollowPageSize, nil
+	})}
+}
+
+// ReputationIterator iterates over account reputations.
+type ReputationIterator struct {
+	*pager
+}
+
+// Value returns the current account reputation.
+func (it *ReputationIterator) Value() *follow.AccountReputation {
+	return it.value.(*follow.AccountReputation)
+}
+
+// AccountReputations iterates over the account reputations
+// in alphabetical order, starting with lowerBound.
+func AccountReputations(api *follow.API, lowerBound string) *ReputationIterator {
+	start, first := lowerBound, true
+	return &ReputationIterator{newPager(func() ([]interface{}, bool, error) {
+		reps, err := api.GetAccountReputations(start, ReputationPageSize)
+		if err != nil {
+			return nil, false, err
+		}
+
+		var items []interface{}
+		for _, rep := range reps {
+			if !first && rep.Account == start {
+				continue
+			}
+			items = append(items, rep)
+		}
+		first = false
+		if len(items) == 0 {
+			return nil, true, nil
+		}
+		start = reps[len(reps)-1].Account
+		return items, len(reps) < ReputationPageSize, nil
+	})}
+}
+
+// BlogIterator iterates over blog entries, the newest first.
+type BlogIterator struct {
+	*pager
+}
+
+// Value returns the current blog entry.
+func (it *BlogIterator) Value() *follow.Blogs {
+	return it.value.(*follow.Blogs)
+}
+
+// Blog iterates over the blog of the account, the newest entry first.
+func Blog(api *follow.API, account string) *BlogIterator {
+	return &BlogIterator{entries(func(start uint32) ([]interface{}, []uint32, error) {
+		blogs, err := api.GetBlog(account, start, BlogPageSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		items := make([]interface{}, len(blogs))
+		ids := make([]uint32, len(blogs))
+		for i, blog := range blogs {
+			items[i], ids[i] = blog, entryID(blog.EntryID)
+		}
+		return items, ids, nil
+	})}
+}
+
+// FeedIterator iterates over feed entries, the newest first.
+type FeedIterator struct {
+	*pager
+}
+
+// Value returns the current feed entry.
+func (it *FeedIterator) Value() *follow.Feeds {
+	return it.value.(*follow.Feeds)
+}
+
+// Feed iterates over the feed of the account, the newest entry first.
+func Feed(api *follow.API, account string) *FeedIterator {
+	return &FeedIterator{entries(func(start uint32) ([]interface{}, []uint32, error) {
+		feeds, err := api.GetFeed(account, start, BlogPageSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		items := make([]interface{}, len(feeds))
+		ids := make([]uint32, len(feeds))
+		for i, feed := range feeds {
+			items[i], ids[i] = feed, entryID(feed.EntryID)
+		}
+		return items, ids, nil
+	})}
+}
+
+// entries pages through entries with descending IDs.
+//
+// Entry ID 0 means the newest entry when used as the start cursor,
+// so the cursor is always set to the last ID seen and the overlap is skipped.
+func entries(get func(start uint32) ([]interface{}, []uint32, error)) *pager {
+	var (
+		start uint32
+		first = true
+	)
+	return newPager(func() ([]interface{}, bool, error) {
+		page, ids, err := get(start)
+		if err != nil {
+			return nil, false, err
+		}
+
+		var items []interface{}
+		for i, item := range page {
+			if !first && ids[i] >= start {
+				continue
+			}
+			items = append(items, item)
+		}
+		first = false
+		if len(items) == 0 {
+			return nil, true, nil
+		}
+		start = ids[len(ids)-1]
+		return items, len(page) < BlogPageSize || start == 0, nil
+	})
+}
+
+func entryID(id *types.Int) uint32 {
+	if id == nil || id.Int == nil {
+		return 0
+	}
+	return uint32(id.Int64())
+}
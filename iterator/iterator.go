@@ -0,0 +1,53 @@
+// Package iterator provides auto-paginating iterators for the list endpoints.
+//
+// All the iterators share the same interface:
+//
+//	it := iterator.Followers(client.Follow, "steemit", "blog")
+//	for it.Next() {
+//		log.Println(it.Value().Follower)
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+//
+// Most of the endpoints include the start cursor in the returned page,
+// the iterators take care of skipping the overlapping entries.
+package iterator
+
+// pager implements the iteration logic shared by all the iterators.
+//
+// fetch is expected to return the next page of new items only,
+// last is set when there are no more pages to fetch.
+type pager struct {
+	fetch func() (items []interface{}, last bool, err error)
+
+	items []interface{}
+	value interface{}
+	done  bool
+	err   error
+}
+
+func newPager(fetch func() ([]interface{}, bool, error)) *pager {
+	return &pager{fetch: fetch}
+}
+
+// Next advances the iterator, false is returned when there are no more items
+// or an error occurred, use Err to tell the difference.
+func (p *pager) Next() bool {
+	for len(p.items) == 0 {
+		if p.done || p.err != nil {
+			return false
+		}
+		p.items, p.done, p.err = p.fetch()
+		if p.err != nil {
+			return false
+		}
+	}
+	p.value, p.items = p.items[0], p.items[1:]
+	return true
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (p *pager) Err() error {
+	return p.err
+}
@@ -0,0 +1,436 @@
+package iterator
+
+import (
+	// Stdlib
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	// RPC
+	"github.com/asuleymanov/rpc/apis/database"
+	"github.com/asuleymanov/rpc/apis/follow"
+	"github.com/asuleymanov/rpc/apis/market"
+)
+
+// fakeNode serves the followers, the account history and the blog of a single account,
+// the account names and reputations, the witnesses ordered by votes and the trade history.
+type fakeNode struct {
+	followers []string
+	history   int
+	accounts  []string
+	witnesses []string
+	blog      int
+	trades    []time.Time
+	// tradePays is the current pays of every trade, the index of the trade by default.
+	tradePays []string
+	calls     int
+}
+
+func (node *fakeNode) Call(method string, params, response interface{}) error {
+	args := params.([]interface{})
+	var result interface{}
+	switch method {
+	case "call":
+		if args[1] == "get_api_by_name" {
+			result = 1
+			break
+		}
+		node.calls++
+		switch args[1] {
+		case "get_followers":
+			result = node.getFollowers(args[2].([]interface{}))
+		case "get_account_reputations":
+			result = node.getAccountReputations(args[2].([]interface{}))
+		case "get_blog":
+			result = node.getBlog(args[2].([]interface{}))
+		case "get_trade_history":
+			result = node.getTradeHistory(args[2].([]interface{}))
+		default:
+			return fmt.Errorf("unexpected method %v", args[1])
+		}
+	case "get_account_history":
+		node.calls++
+		result = node.getAccountHistory(args[1].(int64), args[2].(uint32))
+	case "lookup_accounts":
+		node.calls++
+		result = node.lookupAccounts(args[0].(string), int(args[1].(uint32)))
+	case "get_witnesses_by_vote":
+		node.calls++
+		result = node.getWitnessesByVote(args[0].(string), int(args[1].(uint)))
+	default:
+		return fmt.Errorf("unexpected method %v", method)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, response)
+}
+
+func (node *fakeNode) getFollowers(args []interface{}) []map[string]string {
+	start, limit := args[1].(string), int(args[3].(uint16))
+	var page []map[string]string
+	for _, name := range node.followers {
+		if name >= start && len(page) < limit {
+			page = append(page, map[string]string{"follower": name, "following": "alice"})
+		}
+	}
+	return page
+}
+
+func (node *fakeNode) getAccountHistory(from int64, limit uint32) [][]interface{} {
+	if from < 0 || from >= int64(node.history) {
+		from = int64(node.history) - 1
+	}
+	var page [][]interface{}
+	for seq := from - int64(limit); seq <= from; seq++ {
+		if seq >= 0 {
+			page = append(page, []interface{}{seq, map[string]interface{}{
+				"block": seq,
+				"op":    []interface{}{"vote", map[string]interface{}{"voter": "alice"}},
+			}})
+		}
+	}
+	return page
+}
+
+func (node *fakeNode) lookupAccounts(lowerBound string, limit int) []string {
+	var page []string
+	for _, name := range node.accounts {
+		if name >= lowerBound && len(page) < limit {
+			page = append(page, name)
+		}
+	}
+	return page
+}
+
+func (node *fakeNode) getAccountReputations(args []interface{}) []map[string]interface{} {
+	var page []map[string]interface{}
+	for i, name := range node.lookupAccounts(args[0].(string), int(args[1].(uint32))) {
+		page = append(page, map[string]interface{}{"account": name, "reputation": i})
+	}
+	return page
+}
+
+// getWitnessesByVote returns the witnesses starting with from, the first ones if it is empty.
+func (node *fakeNode) getWitnessesByVote(from string, limit int) []map[string]string {
+	var page []map[string]string
+	for i, name := range node.witnesses {
+		if from != "" && name != from {
+			continue
+		}
+		for _, name := range node.witnesses[i:] {
+			if len(page) == limit {
+				break
+			}
+			page = append(page, map[string]string{"owner": name})
+		}
+		break
+	}
+	return page
+}
+
+// getBlog returns the entries with IDs up to start, the newest ones if it is 0.
+func (node *fakeNode) getBlog(args []interface{}) []map[string]interface{} {
+	start, limit := int(args[1].(uint32)), int(args[2].(uint16))
+	if start == 0 || start >= node.blog {
+		start = node.blog - 1
+	}
+	var page []map[string]interface{}
+	for id := start; id >= 0 && len(page) < limit; id-- {
+		page = append(page, map[string]interface{}{"blog": "alice", "entry_id": id})
+	}
+	return page
+}
+
+func (node *fakeNode) getTradeHistory(args []interface{}) []map[string]string {
+	start, _ := time.Parse(tradeTimeLayout, args[0].(string))
+	end, _ := time.Parse(tradeTimeLayout, args[1].(string))
+	limit := int(args[2].(uint32))
+	var page []map[string]string
+	for i, date := range node.trades {
+		if !date.Before(start) && date.Before(end) && len(page) < limit {
+			page = append(page, map[string]string{
+				"date":         date.Format(tradeTimeLayout),
+				"current_pays": node.tradePaysAt(i),
+				"open_pays":    "1.000 SBD",
+			})
+		}
+	}
+	return page
+}
+
+func (node *fakeNode) tradePaysAt(i int) string {
+	if node.tradePays != nil {
+		return node.tradePays[i]
+	}
+	return fmt.Sprintf("%v.000 STEEM", i)
+}
+
+// names returns count sorted names with the prefix.
+func names(prefix string, count int) []string {
+	var names []string
+	for i := 0; i < count; i++ {
+		names = append(names, fmt.Sprintf("%v%04d", prefix, i))
+	}
+	return names
+}
+
+// expectNames fails unless the names are the expected ones in order, without duplicates.
+func expectNames(t *testing.T, expected, got []string) {
+	t.Helper()
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v names, got %v", len(expected), len(got))
+	}
+	for i, name := range got {
+		if name != expected[i] {
+			t.Fatalf("expected %v at %v, got %v", expected[i], i, name)
+		}
+	}
+}
+
+func TestFollowers(t *testing.T) {
+	node := &fakeNode{}
+	for i := 0; i < 2500; i++ {
+		node.followers = append(node.followers, fmt.Sprintf("user%04d", i))
+	}
+	api, err := follow.NewAPI(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	it := Followers(api, "alice", "blog")
+	for it.Next() {
+		got = append(got, it.Value().Follower)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != len(node.followers) {
+		t.Fatalf("expected %v followers, got %v", len(node.followers), len(got))
+	}
+	for i, name := range got {
+		if name != node.followers[i] {
+			t.Fatalf("expected %v at %v, got %v", node.followers[i], i, name)
+		}
+	}
+	if node.calls != 3 {
+		t.Errorf("expected 3 calls, got %v", node.calls)
+	}
+}
+
+func TestAccountHistory(t *testing.T) {
+	node := &fakeNode{history: 2345}
+	api := database.NewAPI(node)
+
+	expected := uint64(node.history)
+	it := AccountHistory(api, "alice")
+	for it.Next() {
+		expected--
+		if seq := it.Value().Sequence; seq != expected {
+			t.Fatalf("expected sequence %v, got %v", expected, seq)
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if expected != 0 {
+		t.Errorf("iteration stopped at sequence %v", expected)
+	}
+}
+
+func TestLookupAccounts(t *testing.T) {
+	node := &fakeNode{accounts: names("user", 2500)}
+	api := database.NewAPI(node)
+
+	var got []string
+	it := LookupAccounts(api, "user0500")
+	for it.Next() {
+		got = append(got, it.Value())
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectNames(t, node.accounts[500:], got)
+	if node.calls != 3 {
+		t.Errorf("expected 3 calls, got %v", node.calls)
+	}
+}
+
+func TestWitnessesByVote(t *testing.T) {
+	node := &fakeNode{witnesses: names("witness", 250)}
+	api := database.NewAPI(node)
+
+	var got []string
+	it := WitnessesByVote(api)
+	for it.Next() {
+		got = append(got, it.Value().Owner)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectNames(t, node.witnesses, got)
+	if node.calls != 3 {
+		t.Errorf("expected 3 calls, got %v", node.calls)
+	}
+}
+
+func TestDiscussions(t *testing.T) {
+	permlinks := names("post", 250)
+	var calls int
+	fetch := func(query *database.DiscussionQuery) ([]*database.Content, error) {
+		calls++
+		if query.Tag != "golang" {
+			t.Errorf("unexpected tag %v", query.Tag)
+		}
+		var page []*database.Content
+		for i, permlink := range permlinks {
+			if query.StartPermlink != "" && permlink != query.StartPermlink {
+				continue
+			}
+			for _, permlink := range permlinks[i:] {
+				if len(page) == int(query.Limit) {
+					break
+				}
+				page = append(page, &database.Content{Author: "alice", Permlink: permlink})
+			}
+			break
+		}
+		return page, nil
+	}
+
+	var got []string
+	it := Discussions(fetch, &database.DiscussionQuery{Tag: "golang", Limit: 10})
+	for it.Next() {
+		got = append(got, it.Value().Permlink)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectNames(t, permlinks, got)
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %v", calls)
+	}
+}
+
+func TestAccountReputations(t *testing.T) {
+	node := &fakeNode{accounts: names("user", 2000)}
+	api, err := follow.NewAPI(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	it := AccountReputations(api, "")
+	for it.Next() {
+		got = append(got, it.Value().Account)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectNames(t, node.accounts, got)
+	if node.calls != 3 {
+		t.Errorf("expected 3 calls, got %v", node.calls)
+	}
+}
+
+func TestBlog(t *testing.T) {
+	node := &fakeNode{blog: 1234}
+	api, err := follow.NewAPI(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := node.blog
+	it := Blog(api, "alice")
+	for it.Next() {
+		expected--
+		if id := int(entryID(it.Value().EntryID)); id != expected {
+			t.Fatalf("expected entry %v, got %v", expected, id)
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if expected != 0 {
+		t.Errorf("iteration stopped at entry %v", expected)
+	}
+	if node.calls != 3 {
+		t.Errorf("expected 3 calls, got %v", node.calls)
+	}
+}
+
+func TestTradeHistory(t *testing.T) {
+	// Three trades every second, so the pages end in the middle of a second.
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	node := &fakeNode{}
+	for i := 0; i < 2600; i++ {
+		node.trades = append(node.trades, start.Add(time.Duration(i/3)*time.Second))
+	}
+	api, err := market.NewAPI(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The trades of the last second are past the end.
+	end := node.trades[2500]
+	var got []string
+	it := TradeHistory(api, start, end)
+	for it.Next() {
+		got = append(got, it.Value().CurrentPays)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	var expected []string
+	for i, date := range node.trades {
+		if date.Before(end) {
+			expected = append(expected, fmt.Sprintf("%v.000 STEEM", i))
+		}
+	}
+	expectNames(t, expected, got)
+}
+
+func TestTradeHistory_IdenticalFills(t *testing.T) {
+	// Identical fills in the same second on both sides of the first page boundary.
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	node := &fakeNode{}
+	for i := 0; i < 1500; i++ {
+		date := start.Add(time.Duration(i) * time.Second)
+		pays := fmt.Sprintf("%v.000 STEEM", i)
+		if i >= TradePageSize-2 && i < TradePageSize+2 {
+			date, pays = start.Add(time.Duration(TradePageSize)*time.Second), "1.000 STEEM"
+		}
+		node.trades = append(node.trades, date)
+		node.tradePays = append(node.tradePays, pays)
+	}
+	api, err := market.NewAPI(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	it := TradeHistory(api, start, start.Add(time.Hour))
+	for it.Next() {
+		got = append(got, it.Value().CurrentPays)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(node.tradePays) {
+		t.Fatalf("expected %v trades, got %v", len(node.tradePays), len(got))
+	}
+	for i, pays := range node.tradePays {
+		if got[i] != pays {
+			t.Fatalf("trade %v: expected %v, got %v", i, pays, got[i])
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package iterator
+
+import (
+	// Stdlib
+	"time"
+
+	// RPC
+	"github.com/asuleymanov/rpc/apis/market"
+)
+
+// TradePageSize is the page size used for get_trade_history.
+const TradePageSize = 1000
+
+// Time format expected by get_trade_history.
+const tradeTimeLayout = "2006-01-02T15:04:05"
+
+// TradeIterator iterates over filled orders.
+type TradeIterator struct {
+	*pager
+}
+
+// Value returns the current trade.
+func (it *TradeIterator) Value() *market.Trades {
+	return it.value.(*market.Trades)
+}
+
+// TradeHistory iterates over the trades executed between start and end, the oldest first.
+func TradeHistory(api *market.API, start, end time.Time) *TradeIterator {
+	// Trades sharing the timestamp of the cursor are returned again at the beginning of the next page,
+	// skip counts the ones already seen. Trades carry no ID, so identical fills can only be told apart
+	// by their position.
+	var skip int
+	return &TradeIterator{newPager(func() ([]interface{}, bool, error) {
+		trades, err := api.GetTradeHistory(start.UTC().Format(tradeTimeLayout), end.UTC().Format(tradeTimeLayout), TradePageSize)
+		if err != nil {
+			return nil, false, err
+		}
+
+		var items []interface{}
+		for i, trade := range trades {
+			if i < skip && tradeAt(trade, start) {
+				continue
+			}
+			items = append(items, trade)
+		}
+		if len(trades) < TradePageSize {
+			return items, true, nil
+		}
+
+		last := trades[len(trades)-1]
+		if last.Date == nil || last.Date.Time == nil {
+			return items, true, nil
+		}
+		if len(items) == 0 {
+			// The whole page shares a single timestamp, move past it.
+			start = last.Date.Add(time.Second)
+			skip = 0
+			return nil, !start.Before(end), nil
+		}
+		start, skip = *last.Date.Time, 0
+		for _, trade := range trades {
+			if tradeAt(trade, start) {
+				skip++
+			}
+		}
+		return items, false, nil
+	})}
+}
+
+func tradeAt(trade *market.Trades, date time.Time) bool {
+	return trade.Date != nil && trade.Date.Time != nil && trade.Date.Equal(date)
+}
@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	// Stdlib
+	"strings"
+	"sync"
+	"time"
+
+	// RPC
+	"github.com/asuleymanov/rpc/interfaces"
+	"github.com/asuleymanov/rpc/middleware"
+	"github.com/asuleymanov/rpc/transports/websocket"
+	"github.com/asuleymanov/rpc/types"
+)
+
+// Collector gathers Steem RPC client metrics into a Registry.
+//
+// Use Middleware to instrument the calls and ObserveEvent
+// (e.g. via websocket.SetEventHandler) to instrument the transport.
+type Collector struct {
+	calls       *CounterVec
+	latency     *HistogramVec
+	inFlight    *Gauge
+	connects    *CounterVec
+	reconnects  *CounterVec
+	disconnects *CounterVec
+	broadcasts  *CounterVec
+
+	mu        sync.Mutex
+	connected map[string]bool
+}
+
+// NewCollector registers the client metrics into reg.
+func NewCollector(reg *Registry) *Collector {
+	return &Collector{
+		calls: reg.NewCounterVec("steem_rpc_calls_total",
+			"Number of RPC calls by method and outcome.", "method", "outcome"),
+		latency: reg.NewHistogramVec("steem_rpc_call_duration_seconds",
+			"RPC call latency by method.", nil, "method"),
+		inFlight: reg.NewGaugeVec("steem_rpc_calls_in_flight",
+			"Number of RPC calls currently in progress.").With(),
+		connects: reg.NewCounterVec("steem_transport_connects_total",
+			"Number of established connections by URL.", "url"),
+		reconnects: reg.NewCounterVec("steem_transport_reconnects_total",
+			"Number of connections re-established after the first one by URL.", "url"),
+		disconnects: reg.NewCounterVec("steem_transport_disconnects_total",
+			"Number of lost connections and failed connection attempts by URL.", "url"),
+		broadcasts: reg.NewCounterVec("steem_broadcast_operations_total",
+			"Number of broadcasted operations by operation type and result.", "op_type", "result"),
+		connected: make(map[string]bool),
+	}
+}
+
+// Middleware returns a middleware that records call counts, latency,
+// calls in flight and broadcasted operations.
+func (c *Collector) Middleware() middleware.Middleware {
+	return func(next interfaces.Caller) interfaces.Caller {
+		return middleware.CallerFunc(func(method string, params, response interface{}) error {
+			_, name := middleware.MethodName(method, params)
+
+			c.inFlight.Inc()
+			start := time.Now()
+			err := next.Call(method, params, response)
+			c.latency.With(name).Observe(time.Since(start).Seconds())
+			c.inFlight.Dec()
+
+			outcome := "success"
+			if err != nil {
+				outcome = "error"
+			}
+			c.calls.With(name, outcome).Inc()
+
+			if strings.HasPrefix(name, "broadcast_transaction") {
+				for _, opType := range broadcastedOpTypes(params) {
+					c.broadcasts.With(string(opType), outcome).Inc()
+				}
+			}
+			return err
+		})
+	}
+}
+
+// ObserveEvent records a transport event, it can be passed into websocket.SetEventHandler.
+func (c *Collector) ObserveEvent(event interface{}) {
+	switch e := event.(type) {
+	case *websocket.ConnectedEvent:
+		c.connects.With(e.URL).Inc()
+
+		c.mu.Lock()
+		if c.connected[e.URL] {
+			c.reconnects.With(e.URL).Inc()
+		}
+		c.connected[e.URL] = true
+		c.mu.Unlock()
+
+	case *websocket.DisconnectedEvent:
+		c.disconnects.With(e.URL).Inc()
+	}
+}
+
+// broadcastedOpTypes extracts the operation types from broadcast_transaction* call parameters.
+func broadcastedOpTypes(params interface{}) []types.OpType {
+	args, ok := params.([]interface{})
+	if !ok || len(args) == 0 {
+		return nil
+	}
+	// The transaction is wrapped in a parameter list, either directly
+	// or inside of the "call" parameters.
+	if len(args) == 3 {
+		if inner, ok := args[2].([]interface{}); ok {
+			args = inner
+		}
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	tx, ok := args[0].(*types.Transaction)
+	if !ok {
+		return nil
+	}
+
+	opTypes := make([]types.OpType, 0, len(tx.Operations))
+	for _, op := range tx.Operations {
+		opTypes = append(opTypes, op.Type())
+	}
+	return opTypes
+}
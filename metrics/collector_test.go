@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	// Stdlib
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	// RPC
+	"github.com/asuleymanov/rpc/middleware"
+	"github.com/asuleymanov/rpc/transports/websocket"
+	"github.com/asuleymanov/rpc/types"
+)
+
+func exposed(t *testing.T, reg *Registry) string {
+	t.Helper()
+	var b bytes.Buffer
+	if _, err := reg.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func TestCollector(t *testing.T) {
+	reg := NewRegistry()
+	c := NewCollector(reg)
+
+	// The fake node checks the call is counted as in flight, reads fail.
+	node := middleware.CallerFunc(func(method string, params, response interface{}) error {
+		if out := exposed(t, reg); !strings.Contains(out, "steem_rpc_calls_in_flight 1\n") {
+			t.Errorf("expected a call in flight, got\n%v", out)
+		}
+		if method == "get_accounts" {
+			return errors.New("node unavailable")
+		}
+		return nil
+	})
+	caller := middleware.Chain(c.Middleware())(node)
+
+	tx := &types.Transaction{Operations: types.Operations{
+		&types.VoteOperation{Voter: "alice", Author: "bob", Permlink: "post", Weight: 10000},
+		&types.TransferOperation{From: "alice", To: "bob", Amount: "1.000 STEEM"},
+	}}
+	if err := caller.Call("call", []interface{}{3, "broadcast_transaction_synchronous", []interface{}{tx}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := caller.Call("get_accounts", []interface{}{[]string{"alice"}}, nil); err == nil {
+		t.Fatal("expected the read to fail")
+	}
+
+	url := "wss://node.example.com"
+	c.ObserveEvent(&websocket.ConnectedEvent{URL: url})
+	c.ObserveEvent(&websocket.DisconnectedEvent{URL: url, Err: errors.New("EOF")})
+	c.ObserveEvent(&websocket.ConnectedEvent{URL: url})
+
+	out := exposed(t, reg)
+	for _, line := range []string{
+		`steem_rpc_calls_total{method="broadcast_transaction_synchronous",outcome="success"} 1`,
+		`steem_rpc_calls_total{method="get_accounts",outcome="error"} 1`,
+		`steem_rpc_call_duration_seconds_count{method="broadcast_transaction_synchronous"} 1`,
+		`steem_rpc_call_duration_seconds_count{method="get_accounts"} 1`,
+		`steem_rpc_calls_in_flight 0`,
+		`steem_transport_connects_total{url="wss://node.example.com"} 2`,
+		`steem_transport_reconnects_total{url="wss://node.example.com"} 1`,
+		`steem_transport_disconnects_total{url="wss://node.example.com"} 1`,
+		`steem_broadcast_operations_total{op_type="transfer",result="success"} 1`,
+		`steem_broadcast_operations_total{op_type="vote",result="success"} 1`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("expected %v in\n%v", line, out)
+		}
+	}
+	if strings.Contains(out, `op_type="vote",result="error"`) {
+		t.Errorf("unexpected failed broadcast in\n%v", out)
+	}
+}
@@ -0,0 +1,105 @@
+package metrics
+
+// DefaultBuckets are the default histogram buckets, in seconds.
+// They are tailored to measure RPC call latency.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30}
+
+// CounterVec is a set of counters partitioned by label values.
+type CounterVec struct {
+	m *metric
+}
+
+// With returns the counter for the given label values.
+func (vec *CounterVec) With(values ...string) *Counter {
+	return &Counter{vec.m.with(values)}
+}
+
+// Counter is a monotonically increasing value.
+type Counter struct {
+	s *series
+}
+
+// Inc increments the counter by 1.
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add adds the given non-negative value to the counter.
+func (c *Counter) Add(v float64) {
+	if v < 0 {
+		panic("metrics: counter cannot decrease")
+	}
+	c.s.mu.Lock()
+	c.s.value += v
+	c.s.mu.Unlock()
+}
+
+// GaugeVec is a set of gauges partitioned by label values.
+type GaugeVec struct {
+	m *metric
+}
+
+// With returns the gauge for the given label values.
+func (vec *GaugeVec) With(values ...string) *Gauge {
+	return &Gauge{vec.m.with(values)}
+}
+
+// Gauge is a value that can go up and down.
+type Gauge struct {
+	s *series
+}
+
+// Set sets the gauge to the given value.
+func (g *Gauge) Set(v float64) {
+	g.s.mu.Lock()
+	g.s.value = v
+	g.s.mu.Unlock()
+}
+
+// Add adds the given value to the gauge, the value can be negative.
+func (g *Gauge) Add(v float64) {
+	g.s.mu.Lock()
+	g.s.value += v
+	g.s.mu.Unlock()
+}
+
+// Inc increments the gauge by 1.
+func (g *Gauge) Inc() {
+	g.Add(1)
+}
+
+// Dec decrements the gauge by 1.
+func (g *Gauge) Dec() {
+	g.Add(-1)
+}
+
+// HistogramVec is a set of histograms partitioned by label values.
+type HistogramVec struct {
+	m *metric
+}
+
+// With returns the histogram for the given label values.
+func (vec *HistogramVec) With(values ...string) *Histogram {
+	return &Histogram{vec.m, vec.m.with(values)}
+}
+
+// Histogram counts observations in configurable buckets.
+type Histogram struct {
+	m *metric
+	s *series
+}
+
+// Observe adds a single observation to the histogram.
+func (h *Histogram) Observe(v float64) {
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+
+	for i, upper := range h.m.buckets {
+		if v <= upper {
+			h.s.counts[i]++
+			break
+		}
+	}
+	h.s.count++
+	h.s.value += v
+}
@@ -0,0 +1,225 @@
+package metrics
+
+import (
+	// Stdlib
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the content type of the Prometheus text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Registry keeps a set of metrics and renders them
+// in the Prometheus text exposition format.
+//
+// It is a lightweight replacement for the Prometheus client library,
+// there is no need to run Prometheus to use it.
+type Registry struct {
+	mu      sync.Mutex
+	metrics []*metric
+	names   map[string]bool
+}
+
+// NewRegistry creates a new empty registry.
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+// NewCounterVec registers a new counter with the given label names.
+func (reg *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{reg.register(name, help, "counter", labels, nil)}
+}
+
+// NewGaugeVec registers a new gauge with the given label names.
+func (reg *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	return &GaugeVec{reg.register(name, help, "gauge", labels, nil)}
+}
+
+// NewHistogramVec registers a new histogram with the given bucket upper bounds
+// and label names. In case buckets is nil, DefaultBuckets is used.
+func (reg *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	return &HistogramVec{reg.register(name, help, "histogram", labels, sorted)}
+}
+
+func (reg *Registry) register(name, help, kind string, labels []string, buckets []float64) *metric {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if reg.names[name] {
+		panic(fmt.Sprintf("metrics: duplicate metric name: %v", name))
+	}
+	reg.names[name] = true
+
+	m := &metric{
+		name:    name,
+		help:    help,
+		kind:    kind,
+		labels:  labels,
+		buckets: buckets,
+		series:  make(map[string]*series),
+	}
+	reg.metrics = append(reg.metrics, m)
+	return m
+}
+
+// WriteTo writes all registered metrics into w in the text exposition format.
+func (reg *Registry) WriteTo(w io.Writer) (int64, error) {
+	reg.mu.Lock()
+	metrics := append([]*metric(nil), reg.metrics...)
+	reg.mu.Unlock()
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	for _, m := range metrics {
+		m.writeTo(cw)
+	}
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+// ServeHTTP implements http.Handler, so the registry can be mounted at /metrics.
+func (reg *Registry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	reg.WriteTo(w)
+}
+
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) printf(format string, args ...interface{}) {
+	if cw.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(cw.w, format, args...)
+	cw.n += int64(n)
+	cw.err = err
+}
+
+// metric is the shared implementation of all the metric vectors.
+type metric struct {
+	name    string
+	help    string
+	kind    string
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+// series is a single time series, i.e. a metric with concrete label values.
+type series struct {
+	mu     sync.Mutex
+	values []string
+
+	value float64
+
+	// Histograms only.
+	counts []uint64
+	count  uint64
+}
+
+func (m *metric) with(values []string) *series {
+	if len(values) != len(m.labels) {
+		panic(fmt.Sprintf("metrics: %v: expected %v label values, got %v",
+			m.name, len(m.labels), len(values)))
+	}
+
+	key := strings.Join(values, "\xff")
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.series[key]
+	if !ok {
+		s = &series{values: append([]string(nil), values...)}
+		if m.kind == "histogram" {
+			s.counts = make([]uint64, len(m.buckets))
+		}
+		m.series[key] = s
+	}
+	return s
+}
+
+func (m *metric) writeTo(w *countingWriter) {
+	m.mu.Lock()
+	keys := make([]string, 0, len(m.series))
+	for key := range m.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	all := make([]*series, 0, len(keys))
+	for _, key := range keys {
+		all = append(all, m.series[key])
+	}
+	m.mu.Unlock()
+
+	w.printf("# HELP %v %v\n", m.name, escapeHelp(m.help))
+	w.printf("# TYPE %v %v\n", m.name, m.kind)
+
+	for _, s := range all {
+		s.mu.Lock()
+		if m.kind != "histogram" {
+			w.printf("%v%v %v\n", m.name, m.formatLabels(s.values, ""), formatFloat(s.value))
+			s.mu.Unlock()
+			continue
+		}
+
+		var cumulative uint64
+		for i, upper := range m.buckets {
+			cumulative += s.counts[i]
+			w.printf("%v_bucket%v %v\n",
+				m.name, m.formatLabels(s.values, formatFloat(upper)), cumulative)
+		}
+		w.printf("%v_bucket%v %v\n", m.name, m.formatLabels(s.values, "+Inf"), s.count)
+		w.printf("%v_sum%v %v\n", m.name, m.formatLabels(s.values, ""), formatFloat(s.value))
+		w.printf("%v_count%v %v\n", m.name, m.formatLabels(s.values, ""), s.count)
+		s.mu.Unlock()
+	}
+}
+
+func (m *metric) formatLabels(values []string, le string) string {
+	pairs := make([]string, 0, len(values)+1)
+	for i, value := range values {
+		pairs = append(pairs, fmt.Sprintf("%v=\"%v\"", m.labels[i], escapeLabel(value)))
+	}
+	if le != "" {
+		pairs = append(pairs, fmt.Sprintf("le=\"%v\"", le))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpReplacer  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpReplacer.Replace(s)
+}
+
+func escapeLabel(s string) string {
+	return labelReplacer.Replace(s)
+}
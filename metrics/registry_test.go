@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	// Stdlib
+	"bytes"
+	"testing"
+)
+
+func TestRegistry_WriteTo(t *testing.T) {
+	reg := NewRegistry()
+
+	calls := reg.NewCounterVec("calls_total", "Number of calls.", "method")
+	calls.With("get_config").Inc()
+	calls.With("get_block").Add(2)
+
+	latency := reg.NewHistogramVec("latency_seconds", "Call latency.", []float64{1, 0.1})
+	latency.With().Observe(0.05)
+	latency.With().Observe(0.5)
+	latency.With().Observe(3)
+
+	expected := `# HELP calls_total Number of calls.
+# TYPE calls_total counter
+calls_total{method="get_block"} 2
+calls_total{method="get_config"} 1
+# HELP latency_seconds Call latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{le="0.1"} 1
+latency_seconds_bucket{le="1"} 2
+latency_seconds_bucket{le="+Inf"} 3
+latency_seconds_sum 3.55
+latency_seconds_count 3
+`
+
+	var b bytes.Buffer
+	if _, err := reg.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.String(); got != expected {
+		t.Errorf("expected\n%v\ngot\n%v", expected, got)
+	}
+}
+
+func TestEscapeLabel(t *testing.T) {
+	expected := `a\"b\\c\nd`
+	if got := escapeLabel("a\"b\\c\nd"); got != expected {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
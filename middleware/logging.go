@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	// Stdlib
+	"encoding/json"
+	"log"
+	"time"
+
+	// RPC
+	"github.com/asuleymanov/rpc/interfaces"
+)
+
+// CallInfo describes a single finished RPC call.
+type CallInfo struct {
+	// API is the numeric API ID the call was sent to, nil for direct calls.
+	API interface{}
+
+	// Method is the name of the remote method, see MethodName.
+	Method string
+
+	// ParamsSize is the length of the JSON-encoded parameters in bytes.
+	ParamsSize int
+
+	// Latency is the time it took for the call to return.
+	Latency time.Duration
+
+	// Err is the error returned by the call, if any.
+	Err error
+}
+
+// Timing calls fn with the call details every time a call returns.
+func Timing(fn func(info *CallInfo)) Middleware {
+	return func(next interfaces.Caller) interfaces.Caller {
+		return CallerFunc(func(method string, params, response interface{}) error {
+			api, name := MethodName(method, params)
+			start := time.Now()
+			err := next.Call(method, params, response)
+			fn(&CallInfo{
+				API:        api,
+				Method:     name,
+				ParamsSize: paramsSize(params),
+				Latency:    time.Since(start),
+				Err:        err,
+			})
+			return err
+		})
+	}
+}
+
+// Logging logs every call using the given logger in key=value format.
+//
+// In case logger is nil, the standard logger is used.
+func Logging(logger *log.Logger) Middleware {
+	return Timing(func(info *CallInfo) {
+		format := "rpc: method=%v api=%v params_size=%v latency=%v err=%v"
+		args := []interface{}{info.Method, info.API, info.ParamsSize, info.Latency, info.Err}
+		if logger == nil {
+			log.Printf(format, args...)
+		} else {
+			logger.Printf(format, args...)
+		}
+	})
+}
+
+func paramsSize(params interface{}) int {
+	if params == nil {
+		return 0
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
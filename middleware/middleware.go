@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	// Stdlib
+	"encoding/json"
+	"errors"
+	"strings"
+
+	// RPC
+	"github.com/asuleymanov/rpc/interfaces"
+)
+
+// CallerFunc is an adapter to allow the use of ordinary functions as interfaces.Caller.
+type CallerFunc func(method string, params, response interface{}) error
+
+// Call implements interfaces.Caller.
+func (f CallerFunc) Call(method string, params, response interface{}) error {
+	return f(method, params, response)
+}
+
+// Middleware wraps a Caller with additional behaviour, e.g. logging or metrics.
+//
+// A middleware is expected to call next exactly once per call, unless it decides
+// to short-circuit the call completely (e.g. to serve it from a cache).
+type Middleware func(next interfaces.Caller) interfaces.Caller
+
+// Chain composes the given middlewares into a single one.
+//
+// The first middleware is the outermost one, i.e. Chain(a, b)(c) == a(b(c)).
+func Chain(mws ...Middleware) Middleware {
+	return func(next interfaces.Caller) interfaces.Caller {
+		for i := len(mws) - 1; i >= 0; i-- {
+			next = mws[i](next)
+		}
+		return next
+	}
+}
+
+// Wrap applies the given middlewares to the Caller part of cc.
+//
+// The resulting CallCloser can be passed into rpc.NewClient directly,
+// Close is forwarded to the original CallCloser, so are Subscribe
+// and Connections in case cc implements the respective interfaces.
+func Wrap(cc interfaces.CallCloser, mws ...Middleware) interfaces.CallCloser {
+	return &callCloser{Chain(mws...)(cc), cc}
+}
+
+type callCloser struct {
+	caller interfaces.Caller
+	closer interfaces.CallCloser
+}
+
+func (cc *callCloser) Call(method string, params, response interface{}) error {
+	return cc.caller.Call(method, params, response)
+}
+
+func (cc *callCloser) Close() error {
+	return cc.closer.Close()
+}
+
+func (cc *callCloser) Subscribe(
+	method string,
+	params func(callbackID uint32) interface{},
+	notify func(payload json.RawMessage),
+) (func(), error) {
+
+	subscriber, ok := cc.closer.(interfaces.Subscriber)
+	if !ok {
+		return nil, errors.New("transport does not support subscriptions")
+	}
+	return subscriber.Subscribe(method, params, notify)
+}
+
+func (cc *callCloser) Connections() uint64 {
+	if counter, ok := cc.closer.(interfaces.ConnectionCounter); ok {
+		return counter.Connections()
+	}
+	return 0
+}
+
+// MethodName returns the API and the name of the remote method being called.
+//
+// Most of the calls are sent using the "call" method with [apiID, method, params]
+// as the parameters, in which case the API ID and the inner method name are returned.
+// Appbase calls like condenser_api.get_accounts are split into the API name and the method.
+// Otherwise api is nil and the method is returned unchanged.
+func MethodName(method string, params interface{}) (api interface{}, name string) {
+	if i := strings.Index(method, "."); i != -1 {
+		return method[:i], method[i+1:]
+	}
+	if method != "call" {
+		return nil, method
+	}
+	args, ok := params.([]interface{})
+	if !ok || len(args) != 3 {
+		return nil, method
+	}
+	inner, ok := args[1].(string)
+	if !ok {
+		return nil, method
+	}
+	return args[0], inner
+}
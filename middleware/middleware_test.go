@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	// Stdlib
+	"errors"
+	"strings"
+	"testing"
+
+	// RPC
+	"github.com/asuleymanov/rpc/interfaces"
+)
+
+func tag(s string, trace *[]string) Middleware {
+	return func(next interfaces.Caller) interfaces.Caller {
+		return CallerFunc(func(method string, params, response interface{}) error {
+			*trace = append(*trace, s)
+			return next.Call(method, params, response)
+		})
+	}
+}
+
+func TestChain(t *testing.T) {
+	var trace []string
+	final := CallerFunc(func(method string, params, response interface{}) error {
+		trace = append(trace, method)
+		return nil
+	})
+
+	caller := Chain(tag("a", &trace), tag("b", &trace))(final)
+	if err := caller.Call("get_config", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "a,b,get_config"
+	if got := strings.Join(trace, ","); got != expected {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestTiming(t *testing.T) {
+	failure := errors.New("boom")
+	final := CallerFunc(func(method string, params, response interface{}) error {
+		return failure
+	})
+
+	var info *CallInfo
+	caller := Timing(func(i *CallInfo) { info = i })(final)
+	err := caller.Call("call", []interface{}{3, "get_followers", []interface{}{"steemit"}}, nil)
+	if err != failure {
+		t.Errorf("expected %v, got %v", failure, err)
+	}
+
+	if info == nil {
+		t.Fatal("no call info received")
+	}
+	if info.API != 3 || info.Method != "get_followers" || info.Err != failure {
+		t.Errorf("unexpected call info: %+v", info)
+	}
+	if expected := len(`[3,"get_followers",["steemit"]]`); info.ParamsSize != expected {
+		t.Errorf("expected params size %v, got %v", expected, info.ParamsSize)
+	}
+}
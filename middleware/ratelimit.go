@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	// Stdlib
+	"strings"
+	"sync"
+	"time"
+
+	// RPC
+	"github.com/asuleymanov/rpc/interfaces"
+)
+
+// TokenBucket is a token bucket rate limiter.
+//
+// The bucket holds up to burst tokens and is refilled at rate tokens per second.
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// NewTokenBucket creates a new full bucket. It panics when rate is not positive.
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	if rate <= 0 {
+		panic("middleware: the token bucket rate must be positive")
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+		now:    time.Now,
+	}
+}
+
+// Reserve takes a token from the bucket and returns how long
+// the caller must wait before the token can be used.
+func (b *TokenBucket) Reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// Wait blocks until a token is available.
+func (b *TokenBucket) Wait() {
+	if delay := b.Reserve(); delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
+// TokenBuckets holds a TokenBucket per key, e.g. per endpoint URL,
+// all of them with the same rate and burst.
+type TokenBuckets struct {
+	rate  float64
+	burst int
+
+	mu      sync.Mutex
+	buckets map[string]*TokenBucket
+}
+
+// NewTokenBuckets creates the buckets, see NewTokenBucket.
+func NewTokenBuckets(rate float64, burst int) *TokenBuckets {
+	if rate <= 0 {
+		panic("middleware: the token bucket rate must be positive")
+	}
+	return &TokenBuckets{rate: rate, burst: burst, buckets: make(map[string]*TokenBucket)}
+}
+
+// Bucket returns the bucket of the key, a full one is created on first use.
+func (b *TokenBuckets) Bucket(key string) *TokenBucket {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bucket, ok := b.buckets[key]
+	if !ok {
+		bucket = NewTokenBucket(b.rate, b.burst)
+		b.buckets[key] = bucket
+	}
+	return bucket
+}
+
+// IsBroadcast reports whether the call broadcasts a transaction.
+func IsBroadcast(method string, params interface{}) bool {
+	_, name := MethodName(method, params)
+	return strings.HasPrefix(name, "broadcast_")
+}
+
+// RateLimit limits the rate of read calls using the given bucket.
+//
+// Broadcast calls are never delayed, use client.BroadcastScheduler to pace them.
+// Public nodes usually throttle per connection, websocket.SetRateLimit
+// limits the calls with a bucket per endpoint URL instead.
+func RateLimit(bucket *TokenBucket) Middleware {
+	return func(next interfaces.Caller) interfaces.Caller {
+		return CallerFunc(func(method string, params, response interface{}) error {
+			if !IsBroadcast(method, params) {
+				bucket.Wait()
+			}
+			return next.Call(method, params, response)
+		})
+	}
+}
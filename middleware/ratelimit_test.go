@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	// Stdlib
+	"testing"
+	"time"
+)
+
+// clock is a fake time source for the buckets.
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func testBucket(rate float64, burst int) (*TokenBucket, *clock) {
+	c := &clock{now: time.Now()}
+	bucket := NewTokenBucket(rate, burst)
+	bucket.last, bucket.now = c.now, c.Now
+	return bucket, c
+}
+
+func TestTokenBucket_Reserve(t *testing.T) {
+	bucket, c := testBucket(2, 2)
+
+	expect := func(expected time.Duration) {
+		t.Helper()
+		if delay := bucket.Reserve(); delay != expected {
+			t.Errorf("expected a delay of %v, got %v", expected, delay)
+		}
+	}
+
+	// The burst is available immediately, then a token every 500ms.
+	expect(0)
+	expect(0)
+	expect(500 * time.Millisecond)
+	expect(time.Second)
+
+	// 1.5s refill 3 tokens, paying for the 2 reserved ones.
+	c.now = c.now.Add(1500 * time.Millisecond)
+	expect(0)
+	expect(500 * time.Millisecond)
+
+	// The bucket never holds more than the burst.
+	c.now = c.now.Add(time.Minute)
+	expect(0)
+	expect(0)
+	expect(500 * time.Millisecond)
+}
+
+func TestTokenBucket_InvalidRate(t *testing.T) {
+	for _, rate := range []float64{0, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic for rate %v", rate)
+				}
+			}()
+			NewTokenBucket(rate, 1)
+		}()
+	}
+}
+
+func TestTokenBuckets(t *testing.T) {
+	buckets := NewTokenBuckets(1, 1)
+	a := buckets.Bucket("wss://a.example.com")
+	if buckets.Bucket("wss://a.example.com") != a {
+		t.Error("expected the same bucket for the same URL")
+	}
+	if buckets.Bucket("wss://b.example.com") == a {
+		t.Error("expected another bucket for another URL")
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	bucket, _ := testBucket(1, 1)
+	var calls []string
+	final := CallerFunc(func(method string, params, response interface{}) error {
+		calls = append(calls, method)
+		return nil
+	})
+	caller := RateLimit(bucket)(final)
+
+	// The read takes the only token, the broadcasts would have to wait otherwise.
+	if err := caller.Call("get_config", []interface{}{}, nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, params := range [][]interface{}{
+		{3, "broadcast_transaction_synchronous", []interface{}{}},
+		{3, "broadcast_transaction", []interface{}{}},
+	} {
+		if err := caller.Call("call", params, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := caller.Call("condenser_api.broadcast_transaction", []interface{}{}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(calls) != 4 {
+		t.Errorf("expected 4 calls, got %v", calls)
+	}
+	if bucket.tokens != 0 {
+		t.Errorf("expected the broadcasts not to take tokens, %v left", bucket.tokens)
+	}
+}
@@ -0,0 +1,256 @@
+package rewards
+
+import (
+	// Stdlib
+	"math/big"
+	"sort"
+	"time"
+
+	// Vendor
+	"github.com/pkg/errors"
+
+	// RPC
+	"github.com/asuleymanov/rpc/apis/database"
+	"github.com/asuleymanov/rpc/history"
+	"github.com/asuleymanov/rpc/types"
+)
+
+// Curation is the estimated curation reward of a vote.
+type Curation struct {
+	Time    time.Time
+	Rshares *big.Int
+	// Weight is the curation weight of the vote after the reverse auction.
+	Weight *big.Int
+	// Share is the part of the curation rewards of the post the vote earns.
+	// Votes cast after it lower the share.
+	Share *big.Rat
+	// Reward is the estimated curation reward in STEEM at the current price.
+	Reward *types.Asset
+}
+
+// Curation simulates adding a vote with the given rshares to the post at the given time.
+//
+// The votes are replayed in chronological order the way steemd assigns curation weights:
+// the weight of a vote is the growth of the curation curve of the post's vote rshares,
+// reduced linearly during the reverse auction window after the post was created.
+// Votes in the list cast after the given time still lower the share of the simulated vote,
+// the list must not contain an earlier vote of the same voter.
+func (calc *Calculator) Curation(content *database.Content, votes []*database.VoteState, rshares *big.Int, at time.Time) (*Curation, error) {
+	if content.Created == nil || content.Created.Time == nil {
+		return nil, errors.New("rewards: the creation time of the post is unknown")
+	}
+	created := *content.Created.Time
+
+	sorted := make([]*database.VoteState, 0, len(votes))
+	for _, vote := range votes {
+		if vote.Time != nil && vote.Time.Time != nil {
+			sorted = append(sorted, vote)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time.Time.Before(*sorted[j].Time.Time)
+	})
+
+	var (
+		voteRshares = new(big.Int)
+		total       = new(big.Int)
+		weight      = new(big.Int)
+		added       bool
+	)
+	add := func(r *big.Int) *big.Int {
+		if r.Sign() <= 0 {
+			return new(big.Int)
+		}
+		before := calc.CurationWeight(voteRshares)
+		voteRshares.Add(voteRshares, r)
+		max := calc.CurationWeight(voteRshares)
+		max.Sub(max, before)
+		total.Add(total, max)
+		return max
+	}
+	simulate := func() {
+		max := add(rshares)
+		elapsed := at.Sub(created)
+		if window := calc.ReverseAuctionWindow; elapsed < window {
+			if elapsed < 0 {
+				elapsed = 0
+			}
+			max.Mul(max, big.NewInt(int64(elapsed/time.Second)))
+			max.Quo(max, big.NewInt(int64(window/time.Second)))
+		}
+		weight = max
+		added = true
+	}
+	for _, vote := range sorted {
+		if !added && vote.Time.Time.After(at) {
+			simulate()
+		}
+		add(intValue(vote.Rshares))
+	}
+	if !added {
+		simulate()
+	}
+
+	result := &Curation{
+		Time:    at,
+		Rshares: new(big.Int).Set(rshares),
+		Weight:  weight,
+		Share:   new(big.Rat),
+		Reward:  types.NewAsset(new(big.Rat), 3, types.SymbolSteem),
+	}
+	if total.Sign() == 0 || weight.Sign() == 0 || !content.AllowCurationRewards {
+		return result, nil
+	}
+	result.Share.SetFrac(weight, total)
+
+	payout, err := calc.payout(content, new(big.Int).Add(intValue(content.NetRshares), rshares))
+	if err != nil {
+		return nil, err
+	}
+	reward := new(big.Rat).Quo(payout, calc.sbdPerSteem)
+	reward.Mul(reward, big.NewRat(int64(calc.percentCuration), Percent100))
+	reward.Mul(reward, result.Share)
+	result.Reward = types.NewAsset(reward, 3, types.SymbolSteem)
+	return result, nil
+}
+
+// CurationWeight applies the curation curve of the fund to the vote rshares of a post.
+func (calc *Calculator) CurationWeight(rshares *big.Int) *big.Int {
+	if rshares.Sign() <= 0 {
+		return new(big.Int)
+	}
+	r := new(big.Int).Set(rshares)
+	switch calc.curationCurve {
+	case CurveLinear:
+		return r
+	case CurveConvergentSquareRoot:
+		// r / sqrt(r + 2s)
+		root := new(big.Int).Mul(big.NewInt(2), calc.contentConstant)
+		root.Add(root, r).Sqrt(root)
+		return r.Quo(r, root)
+	default:
+		return r.Sqrt(r)
+	}
+}
+
+// CurationRecord is a curation reward received by an account compared to
+// the part of the post's vote rshares its vote had.
+type CurationRecord struct {
+	Time     time.Time
+	Author   string
+	Permlink string
+	// Reward is the curation reward in VESTS.
+	Reward *types.Asset
+	// Rshares is the rshares of the curator's vote on the post.
+	Rshares *big.Int
+	// RsharesShare is the part of the positive vote rshares of the post the vote had.
+	RsharesShare *big.Rat
+	// RewardShare is the part of the curation rewards of the post the curator received.
+	RewardShare *big.Rat
+	// Efficiency is RewardShare / RsharesShare, 1 means the curator got exactly
+	// the share of its rshares, nil when it cannot be computed.
+	Efficiency *big.Rat
+}
+
+// CurationHistory returns the curation rewards of the curator received in [from, to)
+// together with the efficiency of every curated vote.
+//
+// The rewards are compared to the curator payout of the posts. The VESTS and SBD
+// amounts are converted at the current vesting ratio and price, so the efficiency
+// of older rewards is approximate.
+func (calc *Calculator) CurationHistory(api *database.API, curator string, from, to time.Time) ([]*CurationRecord, error) {
+	var records []*CurationRecord
+	w := history.New(api, curator,
+		history.SetOpTypes(types.TypeCurationReward),
+		history.SetTimeRange(from, to),
+	)
+	err := w.Walk(func(entry *database.AccountHistoryEntry) error {
+		op, ok := entry.Operation.Operation.(*types.CurationRewardOperation)
+		if !ok {
+			return nil
+		}
+		record, err := calc.curationRecord(api, curator, op)
+		if err != nil {
+			return err
+		}
+		if entry.Operation.Timestamp != nil && entry.Operation.Timestamp.Time != nil {
+			record.Time = *entry.Operation.Timestamp.Time
+		}
+		records = append(records, record)
+		return nil
+	})
+	return records, err
+}
+
+func (calc *Calculator) curationRecord(api *database.API, curator string, op *types.CurationRewardOperation) (*CurationRecord, error) {
+	reward, err := types.ParseAsset(op.Reward)
+	if err != nil {
+		return nil, errors.Wrap(err, "rewards: invalid curation reward")
+	}
+	record := &CurationRecord{
+		Author:   op.CommentAuthor,
+		Permlink: op.CommentPermlink,
+		Reward:   reward,
+		Rshares:  new(big.Int),
+	}
+
+	votes, err := api.GetActiveVotes(op.CommentAuthor, op.CommentPermlink)
+	if err != nil {
+		return nil, errors.Wrapf(err, "rewards: failed to get votes of %v/%v", op.CommentAuthor, op.CommentPermlink)
+	}
+	total := new(big.Int)
+	for _, vote := range votes {
+		rshares := intValue(vote.Rshares)
+		if rshares.Sign() <= 0 {
+			continue
+		}
+		total.Add(total, rshares)
+		if vote.Voter == curator {
+			record.Rshares = rshares
+		}
+	}
+	if total.Sign() != 0 {
+		record.RsharesShare = new(big.Rat).SetFrac(record.Rshares, total)
+	}
+
+	content, err := api.GetContent(op.CommentAuthor, op.CommentPermlink)
+	if err != nil {
+		return nil, errors.Wrapf(err, "rewards: failed to get %v/%v", op.CommentAuthor, op.CommentPermlink)
+	}
+	if content.CuratorPayoutValue != "" {
+		payout, err := types.ParseAsset(content.CuratorPayoutValue)
+		if err != nil {
+			return nil, errors.Wrap(err, "rewards: invalid curator payout")
+		}
+		if payout.Amount > 0 {
+			steem := calc.VestsToSteem(reward.Rat())
+			record.RewardShare = steem.Quo(steem, new(big.Rat).Quo(payout.Rat(), calc.sbdPerSteem))
+		}
+	}
+
+	if record.RewardShare != nil && record.RsharesShare != nil && record.RsharesShare.Sign() != 0 {
+		record.Efficiency = new(big.Rat).Quo(record.RewardShare, record.RsharesShare)
+	}
+	return record, nil
+}
+
+// CurationEfficiency returns the efficiency of all the records weighted by their rshares,
+// or nil if none of them has an efficiency.
+func CurationEfficiency(records []*CurationRecord) *big.Rat {
+	var (
+		weighted = new(big.Rat)
+		total    = new(big.Rat)
+	)
+	for _, record := range records {
+		if record.Efficiency == nil {
+			continue
+		}
+		rshares := new(big.Rat).SetInt(record.Rshares)
+		weighted.Add(weighted, rshares.Mul(rshares, record.Efficiency))
+		total.Add(total, new(big.Rat).SetInt(record.Rshares))
+	}
+	if total.Sign() == 0 {
+		return nil
+	}
+	return weighted.Quo(weighted, total)
+}
@@ -0,0 +1,209 @@
+package rewards
+
+import (
+	// Stdlib
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	// RPC
+	"github.com/asuleymanov/rpc/apis/database"
+	"github.com/asuleymanov/rpc/types"
+)
+
+var created = time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func newVote(voter string, rshares int64, after time.Duration) *database.VoteState {
+	at := created.Add(after)
+	return &database.VoteState{
+		Voter:   voter,
+		Rshares: &types.Int{Int: big.NewInt(rshares)},
+		Time:    &types.Time{Time: &at},
+	}
+}
+
+func newPost(t *testing.T) *database.Content {
+	var content database.Content
+	if err := json.Unmarshal([]byte(`{
+		"author": "alice",
+		"permlink": "hello",
+		"created": "2018-06-01T00:00:00",
+		"net_rshares": "400000000000000",
+		"max_accepted_payout": "1000000.000 SBD",
+		"allow_curation_rewards": true
+	}`), &content); err != nil {
+		t.Fatal(err)
+	}
+	return &content
+}
+
+func TestCalculator_Curation(t *testing.T) {
+	calc := newCalculator(t, CurveLinear)
+	calc.curationCurve = CurveLinear
+	content := newPost(t)
+	votes := []*database.VoteState{
+		newVote("carol", 300, 40*time.Minute),
+		newVote("bob", 100, 10*time.Minute),
+	}
+
+	cases := []struct {
+		after  time.Duration
+		weight int64
+		share  *big.Rat
+	}{
+		// After both votes, out of the reverse auction.
+		{time.Hour, 100, big.NewRat(1, 5)},
+		// Half way through the auction, the weight is halved but the total is not.
+		{15 * time.Minute, 50, big.NewRat(1, 10)},
+		{5 * time.Minute, 16, big.NewRat(16, 500)},
+		{-time.Minute, 0, new(big.Rat)},
+	}
+	for _, c := range cases {
+		curation, err := calc.Curation(content, votes, big.NewInt(100), created.Add(c.after))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if curation.Weight.Int64() != c.weight || curation.Share.Cmp(c.share) != 0 {
+			t.Errorf("%v: expected weight %v and share %v, got %v and %v",
+				c.after, c.weight, c.share, curation.Weight, curation.Share)
+		}
+	}
+
+	// 800 STEEM, a quarter to the curators, a fifth of it to the vote.
+	curation, err := calc.Curation(content, votes, big.NewInt(100), created.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if curation.Reward.String() != "40.000 STEEM" {
+		t.Errorf("expected 40.000 STEEM, got %v", curation.Reward)
+	}
+
+	content.AllowCurationRewards = false
+	if curation, err = calc.Curation(content, votes, big.NewInt(100), created.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if curation.Share.Sign() != 0 || curation.Reward.Amount != 0 {
+		t.Errorf("expected no curation rewards, got %v, %v", curation.Share, curation.Reward)
+	}
+}
+
+func TestCalculator_CurationSquareRoot(t *testing.T) {
+	calc := newCalculator(t, CurveLinear)
+	content := newPost(t)
+	votes := []*database.VoteState{newVote("bob", 100, 40*time.Minute)}
+
+	// Voting before bob: sqrt(300) = 17, bob gets sqrt(400) - 17 = 3.
+	early, err := calc.Curation(content, votes, big.NewInt(300), created.Add(35*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if early.Share.Cmp(big.NewRat(17, 20)) != 0 {
+		t.Errorf("expected 17/20, got %v", early.Share)
+	}
+
+	// Voting after bob: sqrt(100) = 10, the vote gets sqrt(400) - 10 = 10.
+	late, err := calc.Curation(content, votes, big.NewInt(300), created.Add(45*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if late.Share.Cmp(big.NewRat(1, 2)) != 0 {
+		t.Errorf("expected 1/2, got %v", late.Share)
+	}
+}
+
+// fakeCurator serves the curation rewards of alice and the posts they were paid for.
+type fakeCurator struct{}
+
+var curatedPosts = []struct {
+	permlink string
+	reward   string
+	payout   string
+	rshares  [2]int64
+}{
+	// 1 STEEM of 4 STEEM for a quarter of the rshares.
+	{"first", "2000.000000 VESTS", "2.000 SBD", [2]int64{100, 300}},
+	// 2 STEEM of 4 STEEM for a quarter of the rshares.
+	{"second", "4000.000000 VESTS", "2.000 SBD", [2]int64{100, 300}},
+}
+
+func (node fakeCurator) Call(method string, params, response interface{}) error {
+	var result interface{}
+	switch method {
+	case "get_account_history":
+		args := params.([]interface{})
+		from := args[1].(int64)
+		var page [][]interface{}
+		for seq, post := range curatedPosts {
+			if from >= 0 && int64(seq) > from {
+				break
+			}
+			page = append(page, []interface{}{seq, map[string]interface{}{
+				"block": 100 + seq,
+				"op": []interface{}{"curation_reward", map[string]interface{}{
+					"curator":          "alice",
+					"reward":           post.reward,
+					"comment_author":   "bob",
+					"comment_permlink": post.permlink,
+				}},
+				"timestamp": created.Add(time.Duration(seq) * time.Hour).Format("2006-01-02T15:04:05"),
+			}})
+		}
+		result = page
+	case "get_active_votes", "get_content":
+		args := params.([]string)
+		for _, post := range curatedPosts {
+			if post.permlink != args[1] {
+				continue
+			}
+			if method == "get_content" {
+				result = map[string]interface{}{"author": "bob", "permlink": post.permlink, "curator_payout_value": post.payout}
+			} else {
+				result = []map[string]interface{}{
+					{"voter": "alice", "rshares": post.rshares[0]},
+					{"voter": "carol", "rshares": post.rshares[1]},
+					{"voter": "dave", "rshares": -50},
+				}
+			}
+		}
+	default:
+		return fmt.Errorf("unexpected method %v", method)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, response)
+}
+
+func TestCalculator_CurationHistory(t *testing.T) {
+	calc := newCalculator(t, CurveLinear)
+	records, err := calc.CurationHistory(database.NewAPI(fakeCurator{}), "alice", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %v", len(records))
+	}
+
+	expected := []*big.Rat{big.NewRat(1, 1), big.NewRat(2, 1)}
+	for i, record := range records {
+		if record.Permlink != curatedPosts[i].permlink || record.Rshares.Int64() != 100 {
+			t.Errorf("unexpected record: %+v", record)
+		}
+		if record.RsharesShare.Cmp(big.NewRat(1, 4)) != 0 {
+			t.Errorf("%v: expected a quarter of the rshares, got %v", record.Permlink, record.RsharesShare)
+		}
+		if record.Efficiency == nil || record.Efficiency.Cmp(expected[i]) != 0 {
+			t.Errorf("%v: expected efficiency %v, got %v", record.Permlink, expected[i], record.Efficiency)
+		}
+	}
+	if !records[1].Time.Equal(created.Add(time.Hour)) {
+		t.Errorf("unexpected time: %v", records[1].Time)
+	}
+
+	if efficiency := CurationEfficiency(records); efficiency.Cmp(big.NewRat(3, 2)) != 0 {
+		t.Errorf("expected 3/2, got %v", efficiency)
+	}
+}
@@ -0,0 +1,82 @@
+package rewards
+
+import (
+	// Stdlib
+	"math/big"
+
+	// Vendor
+	"github.com/pkg/errors"
+
+	// RPC
+	"github.com/asuleymanov/rpc/apis/database"
+	"github.com/asuleymanov/rpc/types"
+)
+
+// Payout is the split of the pending payout of a post in SBD.
+type Payout struct {
+	Total    *types.Asset
+	Curation *types.Asset
+	// Author is what the author keeps after the beneficiaries are paid.
+	Author        *types.Asset
+	Beneficiaries map[string]*types.Asset
+}
+
+// PendingPayout estimates the payout of a post from its net rshares and splits it
+// into the curation, beneficiary and author parts.
+// The total is capped at the max accepted payout of the post.
+func (calc *Calculator) PendingPayout(content *database.Content) (*Payout, error) {
+	total, err := calc.payout(content, intValue(content.NetRshares))
+	if err != nil {
+		return nil, err
+	}
+
+	curation := new(big.Rat)
+	if content.AllowCurationRewards {
+		curation.Mul(total, big.NewRat(int64(calc.percentCuration), Percent100))
+	}
+	author := new(big.Rat).Sub(total, curation)
+
+	payout := &Payout{
+		Total:         sbd(total),
+		Curation:      sbd(curation),
+		Beneficiaries: make(map[string]*types.Asset, len(content.Beneficiaries)),
+	}
+	paid := new(big.Rat)
+	for _, beneficiary := range content.Beneficiaries {
+		share := new(big.Rat).Mul(author, big.NewRat(int64(beneficiary.Weight), Percent100))
+		payout.Beneficiaries[beneficiary.Account] = sbd(share)
+		paid.Add(paid, share)
+	}
+	payout.Author = sbd(author.Sub(author, paid))
+	return payout, nil
+}
+
+// payout returns the payout in SBD of the post with the given net rshares.
+func (calc *Calculator) payout(content *database.Content, rshares *big.Int) (*big.Rat, error) {
+	claims := calc.Claims(rshares)
+	if weight := content.RewardWeight; weight != nil && weight.Int != nil && weight.Int64() < Percent100 {
+		claims.Mul(claims, weight.Int).Quo(claims, big.NewInt(Percent100))
+	}
+
+	total := new(big.Rat)
+	if calc.recentClaims.Sign() != 0 {
+		total.SetFrac(claims, calc.recentClaims)
+		total.Mul(total, calc.rewardBalance)
+	}
+	total = calc.SteemToSBD(total)
+
+	if content.MaxAcceptedPayout != "" {
+		max, err := types.ParseAsset(content.MaxAcceptedPayout)
+		if err != nil {
+			return nil, errors.Wrap(err, "rewards: invalid max accepted payout")
+		}
+		if total.Cmp(max.Rat()) > 0 {
+			total = max.Rat()
+		}
+	}
+	return total, nil
+}
+
+func sbd(value *big.Rat) *types.Asset {
+	return types.NewAsset(value, 3, types.SymbolSBD)
+}
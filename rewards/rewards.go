@@ -0,0 +1,343 @@
+// Package rewards estimates voting power, vote values and pending payouts
+// the same way steemd computes them.
+//
+// A Calculator is built from the dynamic global properties, the "post"
+// reward fund and the current median price:
+//
+//	calc, err := rewards.Load(client.Database)
+//	vote, err := calc.Vote(account, 10000, time.Now())
+//	fmt.Println(vote.Rshares, vote.Steem, vote.SBD)
+//
+// The estimates are only as accurate as the data they are made from: the
+// reward fund and the price change with every block.
+package rewards
+
+import (
+	// Stdlib
+	"math/big"
+	"time"
+
+	// Vendor
+	"github.com/pkg/errors"
+
+	// RPC
+	"github.com/asuleymanov/rpc/apis/database"
+	"github.com/asuleymanov/rpc/types"
+)
+
+// Percent100 is 100% in the chain's basis points.
+const Percent100 = 10000
+
+// VoteRegenerationSeconds is the time it takes the voting power to regenerate from 0 to 100%.
+const VoteRegenerationSeconds = 5 * 24 * 60 * 60
+
+// DefaultVotePowerReserveRate is the number of full votes per day used
+// when the global properties do not report vote_power_reserve_rate.
+const DefaultVotePowerReserveRate = 10
+
+// VoteDustThreshold is subtracted from the absolute rshares of every vote since HF20.
+const VoteDustThreshold = 50000000
+
+// DefaultContentConstant is used when the reward fund does not report content_constant.
+const DefaultContentConstant = 2000000000000
+
+// Reward curves used by the reward fund.
+const (
+	CurveLinear           = "linear"
+	CurveQuadratic        = "quadratic"
+	CurveConvergentLinear = "convergent_linear"
+	CurveSquareRoot       = "square_root"
+	// CurveConvergentSquareRoot is only used for curation weights.
+	CurveConvergentSquareRoot = "convergent_square_root"
+)
+
+// DefaultReverseAuctionWindow is the time after the creation of a post during which
+// a part of the curation weight of a vote goes to the author.
+const DefaultReverseAuctionWindow = 30 * time.Minute
+
+// Calculator estimates vote values and payouts for a snapshot of the chain state.
+type Calculator struct {
+	// Now is the chain time used for voting power regeneration.
+	// It defaults to the head block time of the global properties.
+	Now time.Time
+	// ReverseAuctionWindow is used for curation estimates,
+	// set it from STEEMIT_REVERSE_AUCTION_WINDOW_SECONDS of the node config.
+	ReverseAuctionWindow time.Duration
+
+	reserveRate        int64
+	totalVestingFund   *big.Rat
+	totalVestingShares *big.Rat
+	rewardBalance      *big.Rat
+	recentClaims       *big.Int
+	contentConstant    *big.Int
+	curve              string
+	curationCurve      string
+	percentCuration    uint16
+	sbdPerSteem        *big.Rat
+}
+
+// NewCalculator returns a calculator for the given chain state.
+func NewCalculator(props *database.DynamicGlobalProperties, fund *database.RewardFund, price *database.CurrentMedianHistoryPrice) (*Calculator, error) {
+	if props == nil || fund == nil || price == nil {
+		return nil, errors.New("rewards: properties, reward fund and price are required")
+	}
+
+	calc := &Calculator{
+		reserveRate:     DefaultVotePowerReserveRate,
+		curve:           fund.AuthorRewardCurve,
+		curationCurve:   fund.CurationRewardCurve,
+		percentCuration: fund.PercentCurationRewards,
+		recentClaims:    intValue(fund.RecentClaims),
+		contentConstant: big.NewInt(DefaultContentConstant),
+	}
+	if props.Time != nil && props.Time.Time != nil {
+		calc.Now = *props.Time.Time
+	}
+	if rate := intValue(props.VotePowerReserveRate); rate.Sign() > 0 {
+		calc.reserveRate = rate.Int64()
+	}
+	if constant := intValue(fund.ContentConstant); constant.Sign() > 0 {
+		calc.contentConstant = constant
+	}
+	if calc.curve == "" {
+		calc.curve = CurveLinear
+	}
+	if calc.curationCurve == "" {
+		calc.curationCurve = CurveSquareRoot
+	}
+	calc.ReverseAuctionWindow = DefaultReverseAuctionWindow
+
+	var err error
+	if calc.totalVestingFund, err = assetRat(props.TotalVersingFundSteem); err != nil {
+		return nil, err
+	}
+	if calc.totalVestingShares, err = assetRat(props.TotalVestingShares); err != nil {
+		return nil, err
+	}
+	if calc.rewardBalance, err = assetRat(fund.RewardBalance); err != nil {
+		return nil, err
+	}
+	if calc.sbdPerSteem, err = sbdPerSteem(price); err != nil {
+		return nil, err
+	}
+	return calc, nil
+}
+
+// Load fetches the chain state and returns a calculator for it.
+func Load(api *database.API) (*Calculator, error) {
+	props, err := api.GetDynamicGlobalProperties()
+	if err != nil {
+		return nil, errors.Wrap(err, "rewards: failed to get dynamic global properties")
+	}
+	fund, err := api.GetRewardFund("post")
+	if err != nil {
+		return nil, errors.Wrap(err, "rewards: failed to get reward fund")
+	}
+	price, err := api.GetCurrentMedianHistoryPrice()
+	if err != nil {
+		return nil, errors.Wrap(err, "rewards: failed to get median price")
+	}
+	return NewCalculator(props, fund, price)
+}
+
+// VotingPower returns the voting power of the account at the given time in basis points,
+// including the regeneration since the last vote.
+func VotingPower(account *database.Account, now time.Time) int {
+	power := account.VotingPower
+	if account.LastVoteTime != nil && account.LastVoteTime.Time != nil {
+		elapsed := int64(now.Sub(*account.LastVoteTime.Time) / time.Second)
+		if elapsed > 0 {
+			regenerated := int64(Percent100) * elapsed / VoteRegenerationSeconds
+			if int64(power)+regenerated > Percent100 {
+				return Percent100
+			}
+			power += int(regenerated)
+		}
+	}
+	if power > Percent100 {
+		power = Percent100
+	}
+	return power
+}
+
+// EffectiveVestingShares returns the vesting shares the account votes with in satoshis:
+// its own shares minus the delegated ones plus the received ones.
+func EffectiveVestingShares(account *database.Account) (*big.Int, error) {
+	shares, err := assetInt(account.VestingShares)
+	if err != nil {
+		return nil, err
+	}
+	for _, delegation := range []struct {
+		amount string
+		sign   int
+	}{
+		{account.DelegatedVestingShares, -1},
+		{account.ReceivedVestingShares, 1},
+	} {
+		if delegation.amount == "" {
+			continue
+		}
+		value, err := assetInt(delegation.amount)
+		if err != nil {
+			return nil, err
+		}
+		if delegation.sign < 0 {
+			shares.Sub(shares, value)
+		} else {
+			shares.Add(shares, value)
+		}
+	}
+	return shares, nil
+}
+
+// Vote is the estimated effect of a vote.
+type Vote struct {
+	// VotingPower is the voting power before the vote in basis points.
+	VotingPower int
+	// UsedPower is the voting power the vote consumes in basis points.
+	UsedPower int
+	Rshares   *big.Int
+	Steem     *types.Asset
+	SBD       *types.Asset
+}
+
+// Vote estimates the rshares and the value of a vote with the given weight
+// (-10000 to 10000) cast by the account at the given time.
+// A zero time means the calculator's Now.
+//
+// The value is computed for the vote alone. With a non-linear reward curve
+// the actual value also depends on the rshares the post already has.
+func (calc *Calculator) Vote(account *database.Account, weight int, now time.Time) (*Vote, error) {
+	if weight < -Percent100 || weight > Percent100 {
+		return nil, errors.Errorf("rewards: invalid vote weight: %v", weight)
+	}
+	if now.IsZero() {
+		now = calc.Now
+	}
+
+	shares, err := EffectiveVestingShares(account)
+	if err != nil {
+		return nil, err
+	}
+
+	power := VotingPower(account, now)
+	abs := weight
+	if abs < 0 {
+		abs = -abs
+	}
+
+	// Every full vote uses 1/(reserve rate * regeneration days) of the voting power.
+	used := int64(power) * int64(abs) / Percent100
+	denom := calc.reserveRate * VoteRegenerationSeconds / (24 * 60 * 60)
+	used = (used + denom - 1) / denom
+
+	rshares := new(big.Int).Mul(shares, big.NewInt(used))
+	rshares.Quo(rshares, big.NewInt(Percent100))
+	rshares.Sub(rshares, big.NewInt(VoteDustThreshold))
+	if rshares.Sign() < 0 || used == 0 {
+		rshares.SetInt64(0)
+	}
+	if weight < 0 {
+		rshares.Neg(rshares)
+	}
+
+	steem := calc.RsharesToSteem(new(big.Int).Abs(rshares))
+	if weight < 0 {
+		steem.Neg(steem)
+	}
+	return &Vote{
+		VotingPower: power,
+		UsedPower:   int(used),
+		Rshares:     rshares,
+		Steem:       types.NewAsset(steem, 3, types.SymbolSteem),
+		SBD:         types.NewAsset(calc.SteemToSBD(steem), 3, types.SymbolSBD),
+	}, nil
+}
+
+// Claims applies the reward curve of the fund to rshares.
+func (calc *Calculator) Claims(rshares *big.Int) *big.Int {
+	if rshares.Sign() <= 0 {
+		return new(big.Int)
+	}
+	r := new(big.Int).Set(rshares)
+	s := calc.contentConstant
+	switch calc.curve {
+	case CurveQuadratic:
+		// (r + s)^2 - s^2
+		sum := new(big.Int).Add(r, s)
+		return sum.Mul(sum, sum).Sub(sum, new(big.Int).Mul(s, s))
+	case CurveConvergentLinear:
+		// ((r + s)^2 - s^2) / (r + 4s)
+		sum := new(big.Int).Add(r, s)
+		sum.Mul(sum, sum).Sub(sum, new(big.Int).Mul(s, s))
+		return sum.Quo(sum, new(big.Int).Add(r, new(big.Int).Mul(big.NewInt(4), s)))
+	case CurveSquareRoot:
+		return r.Sqrt(r)
+	default:
+		return r
+	}
+}
+
+// RsharesToSteem returns the share of the reward balance rshares are worth.
+func (calc *Calculator) RsharesToSteem(rshares *big.Int) *big.Rat {
+	if calc.recentClaims.Sign() == 0 {
+		return new(big.Rat)
+	}
+	claims := new(big.Rat).SetFrac(calc.Claims(rshares), calc.recentClaims)
+	return claims.Mul(claims, calc.rewardBalance)
+}
+
+// SteemToSBD converts STEEM to SBD at the median price.
+func (calc *Calculator) SteemToSBD(steem *big.Rat) *big.Rat {
+	return new(big.Rat).Mul(steem, calc.sbdPerSteem)
+}
+
+// VestsToSteem converts vesting shares to STEEM at the current vesting fund ratio.
+func (calc *Calculator) VestsToSteem(vests *big.Rat) *big.Rat {
+	if calc.totalVestingShares.Sign() == 0 {
+		return new(big.Rat)
+	}
+	steem := new(big.Rat).Mul(vests, calc.totalVestingFund)
+	return steem.Quo(steem, calc.totalVestingShares)
+}
+
+func intValue(value *types.Int) *big.Int {
+	if value == nil || value.Int == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(value.Int)
+}
+
+func assetRat(value string) (*big.Rat, error) {
+	asset, err := types.ParseAsset(value)
+	if err != nil {
+		return nil, errors.Wrap(err, "rewards")
+	}
+	return asset.Rat(), nil
+}
+
+func assetInt(value string) (*big.Int, error) {
+	asset, err := types.ParseAsset(value)
+	if err != nil {
+		return nil, errors.Wrap(err, "rewards")
+	}
+	return big.NewInt(asset.Amount), nil
+}
+
+func sbdPerSteem(price *database.CurrentMedianHistoryPrice) (*big.Rat, error) {
+	base, err := types.ParseAsset(price.Base)
+	if err != nil {
+		return nil, errors.Wrap(err, "rewards: invalid median price")
+	}
+	quote, err := types.ParseAsset(price.Quote)
+	if err != nil {
+		return nil, errors.Wrap(err, "rewards: invalid median price")
+	}
+	if base.Amount == 0 || quote.Amount == 0 {
+		return nil, errors.Errorf("rewards: invalid median price: %v / %v", price.Base, price.Quote)
+	}
+	if base.Symbol == types.SymbolSteem {
+		base, quote = quote, base
+	}
+	return new(big.Rat).Quo(base.Rat(), quote.Rat()), nil
+}
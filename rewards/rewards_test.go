@@ -0,0 +1,185 @@
+package rewards
+
+import (
+	// Stdlib
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	// RPC
+	"github.com/asuleymanov/rpc/apis/database"
+)
+
+func newCalculator(t *testing.T, curve string) *Calculator {
+	var props database.DynamicGlobalProperties
+	if err := json.Unmarshal([]byte(`{
+		"time": "2018-06-01T12:00:00",
+		"total_vesting_fund_steem": "200000000.000 STEEM",
+		"total_vesting_shares": "400000000000.000000 VESTS",
+		"vote_power_reserve_rate": 10
+	}`), &props); err != nil {
+		t.Fatal(err)
+	}
+	var fund database.RewardFund
+	if err := json.Unmarshal([]byte(`{
+		"name": "post",
+		"reward_balance": "800000.000 STEEM",
+		"recent_claims": "400000000000000000",
+		"content_constant": "2000000000000",
+		"percent_curation_rewards": 2500,
+		"author_reward_curve": "`+curve+`"
+	}`), &fund); err != nil {
+		t.Fatal(err)
+	}
+	price := &database.CurrentMedianHistoryPrice{Base: "0.500 SBD", Quote: "1.000 STEEM"}
+
+	calc, err := NewCalculator(&props, &fund, price)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return calc
+}
+
+func newAccount(t *testing.T, lastVote string) *database.Account {
+	var account database.Account
+	if err := json.Unmarshal([]byte(`{
+		"name": "alice",
+		"voting_power": 8000,
+		"last_vote_time": "`+lastVote+`",
+		"vesting_shares": "1200000.000000 VESTS",
+		"delegated_vesting_shares": "300000.000000 VESTS",
+		"received_vesting_shares": "100000.000000 VESTS"
+	}`), &account); err != nil {
+		t.Fatal(err)
+	}
+	return &account
+}
+
+func TestVotingPower(t *testing.T) {
+	account := newAccount(t, "2018-06-01T00:00:00")
+	start := *account.LastVoteTime.Time
+
+	cases := []struct {
+		elapsed  time.Duration
+		expected int
+	}{
+		{0, 8000},
+		{-time.Hour, 8000},
+		{12 * time.Hour, 9000},
+		{time.Hour, 8083},
+		{24 * time.Hour, 10000},
+		{72 * time.Hour, 10000},
+	}
+	for _, c := range cases {
+		if power := VotingPower(account, start.Add(c.elapsed)); power != c.expected {
+			t.Errorf("%v: expected %v, got %v", c.elapsed, c.expected, power)
+		}
+	}
+}
+
+func TestCalculator_Vote(t *testing.T) {
+	calc := newCalculator(t, CurveLinear)
+	// 12 hours before the head block time, so the voting power is back at 90%.
+	account := newAccount(t, "2018-06-01T00:00:00")
+
+	vote, err := calc.Vote(account, 10000, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 1000000 VESTS effective, 9000 * 100% / 50 = 180 basis points used.
+	if vote.VotingPower != 9000 || vote.UsedPower != 180 {
+		t.Errorf("unexpected voting power: %v, used %v", vote.VotingPower, vote.UsedPower)
+	}
+	if expected := big.NewInt(18000000000 - VoteDustThreshold); vote.Rshares.Cmp(expected) != 0 {
+		t.Errorf("expected %v rshares, got %v", expected, vote.Rshares)
+	}
+	if vote.Steem.String() != "0.035 STEEM" || vote.SBD.String() != "0.017 SBD" {
+		t.Errorf("unexpected value: %v, %v", vote.Steem, vote.SBD)
+	}
+
+	flag, err := calc.Vote(account, -5000, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flag.Rshares.Sign() >= 0 || flag.SBD.Amount >= 0 {
+		t.Errorf("expected a negative flag, got %v, %v", flag.Rshares, flag.SBD)
+	}
+
+	if _, err := calc.Vote(account, 10001, time.Time{}); err == nil {
+		t.Error("expected an error for an invalid weight")
+	}
+}
+
+func TestCalculator_Claims(t *testing.T) {
+	cases := []struct {
+		curve    string
+		rshares  int64
+		expected string
+	}{
+		{CurveLinear, 100, "100"},
+		{CurveLinear, -100, "0"},
+		// (1e12 + 2e12)^2 - (2e12)^2
+		{CurveQuadratic, 1000000000000, "5000000000000000000000000"},
+		// ((2e12 + 2e12)^2 - (2e12)^2) / (2e12 + 8e12)
+		{CurveConvergentLinear, 2000000000000, "1200000000000"},
+		{CurveSquareRoot, 1000000, "1000"},
+	}
+	for _, c := range cases {
+		calc := newCalculator(t, c.curve)
+		if claims := calc.Claims(big.NewInt(c.rshares)); claims.String() != c.expected {
+			t.Errorf("%v(%v): expected %v, got %v", c.curve, c.rshares, c.expected, claims)
+		}
+	}
+}
+
+func TestCalculator_PendingPayout(t *testing.T) {
+	calc := newCalculator(t, CurveLinear)
+
+	var content database.Content
+	if err := json.Unmarshal([]byte(`{
+		"author": "alice",
+		"permlink": "hello",
+		"net_rshares": "400000000000000",
+		"max_accepted_payout": "1000000.000 SBD",
+		"allow_curation_rewards": true,
+		"beneficiaries": [{"account": "bob", "weight": 1000}, {"account": "carol", "weight": 500}]
+	}`), &content); err != nil {
+		t.Fatal(err)
+	}
+
+	payout, err := calc.PendingPayout(&content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 800 STEEM at 0.5 SBD, a quarter to the curators, 15% of the rest to the beneficiaries.
+	expected := map[string]string{
+		"total":    "400.000 SBD",
+		"curation": "100.000 SBD",
+		"author":   "255.000 SBD",
+		"bob":      "30.000 SBD",
+		"carol":    "15.000 SBD",
+	}
+	got := map[string]string{
+		"total":    payout.Total.String(),
+		"curation": payout.Curation.String(),
+		"author":   payout.Author.String(),
+		"bob":      payout.Beneficiaries["bob"].String(),
+		"carol":    payout.Beneficiaries["carol"].String(),
+	}
+	for key, value := range expected {
+		if got[key] != value {
+			t.Errorf("%v: expected %v, got %v", key, value, got[key])
+		}
+	}
+
+	content.MaxAcceptedPayout = "100.000 SBD"
+	content.AllowCurationRewards = false
+	content.Beneficiaries = nil
+	if payout, err = calc.PendingPayout(&content); err != nil {
+		t.Fatal(err)
+	}
+	if payout.Total.String() != "100.000 SBD" || payout.Author.String() != "100.000 SBD" || payout.Curation.Amount != 0 {
+		t.Errorf("unexpected capped payout: %+v", payout)
+	}
+}
@@ -0,0 +1,107 @@
+package websocket
+
+import (
+	// Stdlib
+	"context"
+	"encoding/json"
+
+	// Vendor
+	"github.com/asuleymanov/jsonrpc2"
+	"github.com/pkg/errors"
+)
+
+type subscription struct {
+	method string
+	params func(callbackID uint32) interface{}
+	notify func(payload json.RawMessage)
+
+	// active is set once the initial call succeeds,
+	// only active subscriptions are renewed on reconnect.
+	active bool
+}
+
+// Subscribe implements interfaces.Subscriber.
+//
+// The notify function is called from the connection read loop,
+// so it must not block, otherwise no more responses are received.
+func (t *Transport) Subscribe(
+	method string,
+	params func(callbackID uint32) interface{},
+	notify func(payload json.RawMessage),
+) (func(), error) {
+
+	// The subscription is registered before the call
+	// so that no notice sent right after the response is missed.
+	sub := &subscription{method: method, params: params, notify: notify}
+	t.subsMu.Lock()
+	t.nextCallbackID++
+	id := t.nextCallbackID
+	t.subs[id] = sub
+	t.subsMu.Unlock()
+
+	if err := t.Call(method, params(id), nil); err != nil {
+		t.subsMu.Lock()
+		delete(t.subs, id)
+		t.subsMu.Unlock()
+		return nil, errors.Wrapf(err, "failed to subscribe using %v", method)
+	}
+
+	t.subsMu.Lock()
+	sub.active = true
+	t.subsMu.Unlock()
+
+	return func() {
+		t.subsMu.Lock()
+		delete(t.subs, id)
+		t.subsMu.Unlock()
+	}, nil
+}
+
+// resubscribe renews all active subscriptions over a new connection.
+func (t *Transport) resubscribe(ctx context.Context, conn *jsonrpc2.Conn) {
+	t.subsMu.Lock()
+	subs := make(map[uint32]*subscription, len(t.subs))
+	for id, sub := range t.subs {
+		if sub.active {
+			subs[id] = sub
+		}
+	}
+	t.subsMu.Unlock()
+
+	for id, sub := range subs {
+		if err := conn.Call(ctx, sub.method, sub.params(id), nil); err != nil {
+			// The connection is most probably broken again,
+			// the subscriptions are renewed on the next reconnect.
+			return
+		}
+	}
+}
+
+// noticeHandler routes server-initiated notices to the subscriptions.
+//
+// A notice looks like {"method":"notice","params":[callbackID,[payload]]}.
+type noticeHandler struct {
+	t *Transport
+}
+
+func (h noticeHandler) Handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	if req.Method != "notice" || req.Params == nil {
+		return
+	}
+
+	var params []json.RawMessage
+	if err := json.Unmarshal(*req.Params, &params); err != nil || len(params) != 2 {
+		return
+	}
+	var id uint32
+	if err := json.Unmarshal(params[0], &id); err != nil {
+		return
+	}
+
+	h.t.subsMu.Lock()
+	sub, ok := h.t.subs[id]
+	h.t.subsMu.Unlock()
+	if ok {
+		sub.notify(params[1])
+	}
+}
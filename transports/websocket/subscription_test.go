@@ -0,0 +1,176 @@
+package websocket
+
+import (
+	// Stdlib
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	// Vendor
+	"github.com/gorilla/websocket"
+)
+
+// fakeNotifier is a node accepting set_block_applied_callback subscriptions
+// and sending notices to the subscribed callbacks over the current connection.
+type fakeNotifier struct {
+	mu   sync.Mutex
+	conn *websocket.Conn
+
+	// subscribed receives the callback ID of every subscription call.
+	subscribed chan uint32
+}
+
+type fakeRequest struct {
+	ID     uint64            `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+func (node *fakeNotifier) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	upgrader := websocket.Upgrader{}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	node.mu.Lock()
+	node.conn = conn
+	node.mu.Unlock()
+
+	for {
+		var req fakeRequest
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": nil}
+		switch req.Method {
+		case "set_block_applied_callback":
+			var id uint32
+			json.Unmarshal(req.Params[0], &id)
+			node.subscribed <- id
+		case "get_config":
+			resp["result"] = map[string]interface{}{}
+		default:
+			delete(resp, "result")
+			resp["error"] = map[string]interface{}{"code": 1, "message": "unknown method"}
+		}
+		if err := node.write(conn, resp); err != nil {
+			return
+		}
+	}
+}
+
+func (node *fakeNotifier) write(conn *websocket.Conn, v interface{}) error {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	return conn.WriteJSON(v)
+}
+
+// notify sends a notice for the callback over the current connection.
+func (node *fakeNotifier) notify(t *testing.T, id uint32, payload string) {
+	node.mu.Lock()
+	conn := node.conn
+	node.mu.Unlock()
+	notice := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "notice",
+		"params":  []interface{}{id, []string{payload}},
+	}
+	if err := node.write(conn, notice); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// drop closes the current connection.
+func (node *fakeNotifier) drop() {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	node.conn.Close()
+}
+
+func expectNotice(t *testing.T, notices <-chan string, expected string) {
+	t.Helper()
+	select {
+	case got := <-notices:
+		if got != expected {
+			t.Errorf("expected notice %v, got %v", expected, got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("notice %v not received", expected)
+	}
+}
+
+func expectSubscription(t *testing.T, node *fakeNotifier, expected uint32) {
+	t.Helper()
+	select {
+	case id := <-node.subscribed:
+		if id != expected {
+			t.Errorf("expected callback %v, got %v", expected, id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no subscription received")
+	}
+}
+
+func TestTransport_Subscribe(t *testing.T) {
+	node := &fakeNotifier{subscribed: make(chan uint32, 10)}
+	server := httptest.NewServer(node)
+	defer server.Close()
+
+	transport, err := NewTransport([]string{"ws" + strings.TrimPrefix(server.URL, "http")},
+		SetAutoReconnectEnabled(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer transport.Close()
+
+	notices := make(chan string, 10)
+	notify := func(payload json.RawMessage) {
+		var values []string
+		json.Unmarshal(payload, &values)
+		notices <- strings.Join(values, ",")
+	}
+	params := func(id uint32) interface{} {
+		return []interface{}{id}
+	}
+
+	unsubscribe, err := transport.Subscribe("set_block_applied_callback", params, notify)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectSubscription(t, node, 1)
+	node.notify(t, 1, "block-1")
+	expectNotice(t, notices, "block-1")
+
+	// A failed subscription is not kept.
+	if _, err := transport.Subscribe("set_unknown_callback", params, notify); err == nil {
+		t.Error("expected an error for an unknown subscription method")
+	}
+
+	// After a reconnect the active subscription is renewed with the same callback ID.
+	node.drop()
+	var config map[string]interface{}
+	if err := transport.Call("get_config", []interface{}{}, &config); err != nil {
+		t.Fatal(err)
+	}
+	expectSubscription(t, node, 1)
+	select {
+	case id := <-node.subscribed:
+		t.Errorf("unexpected subscription %v", id)
+	default:
+	}
+	node.notify(t, 1, "block-2")
+	expectNotice(t, notices, "block-2")
+
+	// Notices for a cancelled subscription are dropped.
+	unsubscribe()
+	node.notify(t, 1, "block-3")
+	select {
+	case got := <-notices:
+		t.Errorf("unexpected notice %v after unsubscribe", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
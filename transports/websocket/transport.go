@@ -5,6 +5,8 @@ import (
 	// Stdlib
 	"context"
 	"net"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	// Vendor
@@ -13,6 +15,9 @@ import (
 
 	"github.com/asuleymanov/jsonrpc2"
 	tomb "gopkg.in/tomb.v2"
+
+	// RPC
+	"github.com/asuleymanov/rpc/middleware"
 )
 
 const (
@@ -29,6 +34,10 @@ var netDialer net.Dialer
 
 // Transport implements a CallCloser accessing the Steem RPC endpoint over WebSocket.
 type Transport struct {
+	// Number of connections established so far, accessed atomically.
+	// Keep it first in the struct for 64-bit alignment.
+	connections uint64
+
 	// URLs as passed into the constructor.
 	urls         []string
 	nextURLIndex int
@@ -42,15 +51,28 @@ type Transport struct {
 	autoReconnectEnabled  bool
 	autoReconnectMaxDelay time.Duration
 
-	monitorChan chan<- interface{}
+	monitorChan  chan<- interface{}
+	eventHandler func(event interface{})
+	rateLimit    *middleware.TokenBuckets
+
+	// Active subscriptions by callback ID.
+	subsMu         sync.Mutex
+	subs           map[uint32]*subscription
+	nextCallbackID uint32
 
 	// The underlying JSON-RPC connection.
-	connCh chan chan *jsonrpc2.Conn
+	connCh chan chan connection
 	errCh  chan error
 
 	t *tomb.Tomb
 }
 
+// connection is a JSON-RPC connection along with the URL it is connected to.
+type connection struct {
+	*jsonrpc2.Conn
+	url string
+}
+
 // Option represents an option that can be passed into the transport constructor.
 type Option func(*Transport)
 
@@ -129,6 +151,25 @@ func SetMonitor(monitorChan chan<- interface{}) Option {
 	}
 }
 
+// SetEventHandler can be used to set a function that is called synchronously
+// for every connection-related event, e.g. *ConnectedEvent.
+//
+// Unlike the monitoring channel, no event is ever dropped,
+// so the handler is expected to return quickly.
+func SetEventHandler(handler func(event interface{})) Option {
+	return func(t *Transport) {
+		t.eventHandler = handler
+	}
+}
+
+// SetRateLimit can be used to limit the rate of read calls with a bucket per URL,
+// public nodes usually throttle per connection. Broadcast calls are never delayed.
+func SetRateLimit(buckets *middleware.TokenBuckets) Option {
+	return func(t *Transport) {
+		t.rateLimit = buckets
+	}
+}
+
 // NewTransport creates a new transport that connects to the given WebSocket URLs.
 //
 // It is possible to specify multiple WebSocket endpoint URLs.
@@ -142,8 +183,9 @@ func NewTransport(urls []string, options ...Option) (*Transport, error) {
 		readTimeout:           DefaultReadTimeout,
 		writeTimeout:          DefaultWriteTimeout,
 		autoReconnectMaxDelay: DefaultAutoReconnectMaxDelay,
-		connCh:                make(chan chan *jsonrpc2.Conn),
+		connCh:                make(chan chan connection),
 		errCh:                 make(chan error),
+		subs:                  make(map[uint32]*subscription),
 		t:                     &tomb.Tomb{},
 	}
 
@@ -166,7 +208,7 @@ func (t *Transport) Call(method string, params, result interface{}) error {
 Loop:
 	for {
 		// Request a connection.
-		connCh := make(chan *jsonrpc2.Conn, 1)
+		connCh := make(chan connection, 1)
 		select {
 		case t.connCh <- connCh:
 		case <-ctx.Done():
@@ -176,6 +218,10 @@ Loop:
 		// Receive the connection.
 		conn := <-connCh
 
+		if t.rateLimit != nil && !middleware.IsBroadcast(method, params) {
+			t.rateLimit.Bucket(conn.url).Wait()
+		}
+
 		// Perform the call.
 		err := conn.Call(ctx, method, params, result)
 		if err == nil {
@@ -193,8 +239,9 @@ Loop:
 		}
 
 		// In case this is a connection error, request a new connection.
+		// jsonrpc2 reports a connection closed by the node as ErrClosed.
 		err = errors.Cause(err)
-		if _, ok := err.(*websocket.CloseError); ok || err == io.ErrUnexpectedEOF {
+		if _, ok := err.(*websocket.CloseError); ok || err == io.ErrUnexpectedEOF || err == jsonrpc2.ErrClosed {
 			select {
 			case t.errCh <- errors.Wrap(err, "WebSocket closed"):
 				continue Loop
@@ -227,6 +274,7 @@ func (t *Transport) dialer() error {
 			var err error
 			conn, err = t.dial(ctx)
 			if err == nil {
+				t.resubscribe(ctx, conn)
 				break
 			}
 
@@ -249,7 +297,7 @@ func (t *Transport) dialer() error {
 	for {
 		select {
 		case connCh := <-t.connCh:
-			connCh <- conn
+			connCh <- connection{conn, t.currentURL}
 
 		case err := <-t.errCh:
 			conn.Close()
@@ -284,14 +332,23 @@ func (t *Transport) dial(ctx context.Context) (*jsonrpc2.Conn, error) {
 		t.emit(&DisconnectedEvent{u, err})
 		return nil, err
 	}
+	atomic.AddUint64(&t.connections, 1)
 	t.emit(&ConnectedEvent{u})
 
 	// Wrap the WebSocket with JSON-RPC2.
 	stream := NewObjectStream(ws, t.writeTimeout, t.readTimeout)
-	return jsonrpc2.NewConn(ctx, stream, nil), nil
+	return jsonrpc2.NewConn(ctx, stream, noticeHandler{t}), nil
+}
+
+// Connections implements interfaces.ConnectionCounter.
+func (t *Transport) Connections() uint64 {
+	return atomic.LoadUint64(&t.connections)
 }
 
 func (t *Transport) emit(v interface{}) {
+	if t.eventHandler != nil {
+		t.eventHandler(v)
+	}
 	if t.monitorChan != nil {
 		select {
 		case t.monitorChan <- v:
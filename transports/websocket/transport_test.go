@@ -0,0 +1,50 @@
+package websocket
+
+import (
+	// Stdlib
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	// RPC
+	"github.com/asuleymanov/rpc/middleware"
+)
+
+func TestTransport_RateLimit(t *testing.T) {
+	node := &fakeNotifier{subscribed: make(chan uint32, 10)}
+	server := httptest.NewServer(node)
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	buckets := middleware.NewTokenBuckets(5, 1)
+	transport, err := NewTransport([]string{url}, SetRateLimit(buckets))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer transport.Close()
+
+	// The second read waits for a token of the bucket of the URL.
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		var config map[string]interface{}
+		if err := transport.Call("get_config", []interface{}{}, &config); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("expected the reads to be limited, took %v", elapsed)
+	}
+
+	// Broadcasts are not delayed, the fake node rejects them.
+	start = time.Now()
+	for i := 0; i < 3; i++ {
+		transport.Call("call", []interface{}{3, "broadcast_transaction_synchronous", []interface{}{}}, nil)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("expected the broadcasts not to be limited, took %v", elapsed)
+	}
+	if delay := buckets.Bucket(url).Reserve(); delay == 0 {
+		t.Error("expected the bucket of the URL to be used")
+	}
+}
@@ -0,0 +1,38 @@
+package types
+
+import (
+	// Stdlib
+	"strings"
+
+	// Vendor
+	"github.com/pkg/errors"
+)
+
+// ValidateAccountName checks the name the way steemd does: 3 to 16 characters,
+// dot separated parts of at least 3 lowercase letters, digits and dashes,
+// each starting with a letter and ending with a letter or a digit.
+func ValidateAccountName(name string) error {
+	if len(name) < 3 || len(name) > 16 {
+		return errors.Errorf("account name %q must be 3 to 16 characters long", name)
+	}
+	for _, part := range strings.Split(name, ".") {
+		if len(part) < 3 {
+			return errors.Errorf("account name %q has a part shorter than 3 characters", name)
+		}
+		if part[0] < 'a' || part[0] > 'z' {
+			return errors.Errorf("account name %q has a part not starting with a letter", name)
+		}
+		if last := part[len(part)-1]; !(last >= 'a' && last <= 'z' || last >= '0' && last <= '9') {
+			return errors.Errorf("account name %q has a part not ending with a letter or a digit", name)
+		}
+		for i := 1; i < len(part); i++ {
+			switch c := part[i]; {
+			case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+			case c == '-' && part[i-1] != '-':
+			default:
+				return errors.Errorf("account name %q has an invalid character at %q", name, part)
+			}
+		}
+	}
+	return nil
+}
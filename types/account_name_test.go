@@ -0,0 +1,26 @@
+package types
+
+import (
+	// Stdlib
+	"testing"
+)
+
+func TestValidateAccountName(t *testing.T) {
+	for name, valid := range map[string]bool{
+		"alice":             true,
+		"bob-2":             true,
+		"alice.bob":         true,
+		"ab":                false,
+		"Alice":             false,
+		"1alice":            false,
+		"alice-":            false,
+		"ali--ce":           false,
+		"alice.bo":          false,
+		"alice_bob":         false,
+		"averyveryverylong": false,
+	} {
+		if err := ValidateAccountName(name); (err == nil) != valid {
+			t.Errorf("%v: expected valid %v, got %v", name, valid, err)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package types
+
+import (
+	// Stdlib
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"strings"
+
+	// RPC
+	"github.com/asuleymanov/rpc/encoding/transaction"
+
+	// Vendor
+	"github.com/pkg/errors"
+)
+
+// Asset symbols used by the chain.
+const (
+	SymbolSteem = "STEEM"
+	SymbolSBD   = "SBD"
+	SymbolVests = "VESTS"
+)
+
+// Asset is an amount like "1.000 STEEM" kept as an integer number of satoshis.
+type Asset struct {
+	Amount    int64
+	Precision uint8
+	Symbol    string
+}
+
+// ParseAsset parses an amount like "1.000 STEEM".
+func ParseAsset(s string) (*Asset, error) {
+	parts := strings.Split(strings.TrimSpace(s), " ")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, errors.Errorf("types: invalid asset: %v", s)
+	}
+
+	number := parts[0]
+	var precision int
+	if ind := strings.Index(number, "."); ind != -1 {
+		precision = len(number) - ind - 1
+		number = number[:ind] + number[ind+1:]
+	}
+	if precision > 18 {
+		return nil, errors.Errorf("types: invalid asset precision: %v", s)
+	}
+
+	amount, err := strconv.ParseInt(number, 10, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "types: invalid asset amount: %v", s)
+	}
+	return &Asset{Amount: amount, Precision: uint8(precision), Symbol: parts[1]}, nil
+}
+
+// NewAsset converts value to an asset, rounding towards zero to the given precision.
+func NewAsset(value *big.Rat, precision uint8, symbol string) *Asset {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(precision)), nil)
+	amount := new(big.Int).Mul(value.Num(), scale)
+	amount.Quo(amount, value.Denom())
+	return &Asset{Amount: amount.Int64(), Precision: precision, Symbol: symbol}
+}
+
+// Rat returns the exact value of the asset.
+func (asset *Asset) Rat() *big.Rat {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(asset.Precision)), nil)
+	return new(big.Rat).SetFrac(big.NewInt(asset.Amount), scale)
+}
+
+// String formats the asset the way the chain does, e.g. "1.000 STEEM".
+func (asset *Asset) String() string {
+	amount := asset.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	number := strconv.FormatInt(amount, 10)
+	if asset.Precision > 0 {
+		p := int(asset.Precision)
+		if len(number) <= p {
+			number = strings.Repeat("0", p-len(number)+1) + number
+		}
+		number = number[:len(number)-p] + "." + number[len(number)-p:]
+	}
+	return sign + number + " " + asset.Symbol
+}
+
+// UnmarshalJSON decodes an asset string.
+func (asset *Asset) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return errors.Wrapf(err, "types: failed to unmarshal asset: %v", string(data))
+	}
+	parsed, err := ParseAsset(s)
+	if err != nil {
+		return err
+	}
+	*asset = *parsed
+	return nil
+}
+
+// MarshalJSON encodes the asset as a string.
+func (asset *Asset) MarshalJSON() ([]byte, error) {
+	return json.Marshal(asset.String())
+}
+
+// MarshalTransaction implements transaction.Marshaller interface.
+func (asset *Asset) MarshalTransaction(encoder *transaction.Encoder) error {
+	return encoder.EncodeMoney(asset.String())
+}
@@ -0,0 +1,67 @@
+package types
+
+import (
+	// Stdlib
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestParseAsset(t *testing.T) {
+	cases := []struct {
+		in       string
+		expected Asset
+	}{
+		{"1.000 STEEM", Asset{1000, 3, SymbolSteem}},
+		{"0.001 SBD", Asset{1, 3, SymbolSBD}},
+		{"123456.789012 VESTS", Asset{123456789012, 6, SymbolVests}},
+		{"-2.500 SBD", Asset{-2500, 3, SymbolSBD}},
+		{"10 GOLOS", Asset{10, 0, "GOLOS"}},
+	}
+	for _, c := range cases {
+		asset, err := ParseAsset(c.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *asset != c.expected {
+			t.Errorf("%v: expected %+v, got %+v", c.in, c.expected, *asset)
+		}
+		if asset.String() != c.in {
+			t.Errorf("expected %v, got %v", c.in, asset.String())
+		}
+	}
+
+	for _, in := range []string{"", "1.000", "STEEM", "1.0.0 STEEM", "abc STEEM", "1.000  STEEM"} {
+		if _, err := ParseAsset(in); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+}
+
+func TestAsset_JSON(t *testing.T) {
+	var v struct {
+		Amount *Asset `json:"amount"`
+	}
+	if err := json.Unmarshal([]byte(`{"amount":"0.010 STEEM"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Amount.Rat().Cmp(big.NewRat(1, 100)) != 0 {
+		t.Errorf("expected 0.01, got %v", v.Amount.Rat())
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"amount":"0.010 STEEM"}` {
+		t.Errorf("unexpected JSON: %v", string(data))
+	}
+}
+
+func TestNewAsset(t *testing.T) {
+	if got := NewAsset(big.NewRat(2, 3), 3, SymbolSBD).String(); got != "0.666 SBD" {
+		t.Errorf("expected 0.666 SBD, got %v", got)
+	}
+	if got := NewAsset(big.NewRat(-2, 3), 3, SymbolSBD).String(); got != "-0.666 SBD" {
+		t.Errorf("expected -0.666 SBD, got %v", got)
+	}
+}
@@ -0,0 +1,218 @@
+package types
+
+import (
+	// Stdlib
+	"bytes"
+	"math"
+	"sort"
+
+	// RPC
+	"github.com/asuleymanov/rpc/encoding/transaction"
+	"github.com/asuleymanov/rpc/encoding/wif"
+
+	// Vendor
+	"github.com/pkg/errors"
+)
+
+// MaxAuthorityMembership is STEEMIT_MAX_AUTHORITY_MEMBERSHIP,
+// the maximum number of keys and accounts in an authority.
+const MaxAuthorityMembership = 10
+
+// Authority is a set of keys and accounts allowed to sign for an account role.
+// A transaction is authorized when the weights of its signers add up to WeightThreshold,
+// so a threshold of 2 with three keys of weight 1 is a 2-of-3 multisig.
+type Authority struct {
+	AccountAuths    StringInt64Map `json:"account_auths"`
+	KeyAuths        StringInt64Map `json:"key_auths"`
+	WeightThreshold uint32         `json:"weight_threshold"`
+}
+
+// NewAuthority returns an authority satisfied by a signature of any of the public keys.
+func NewAuthority(keys ...string) *Authority {
+	auth := &Authority{WeightThreshold: 1}
+	for _, key := range keys {
+		setWeight(&auth.KeyAuths, key, 1)
+	}
+	return auth
+}
+
+// Clone returns a copy of the authority that can be changed independently.
+func (auth *Authority) Clone() *Authority {
+	clone := &Authority{WeightThreshold: auth.WeightThreshold}
+	for account, weight := range auth.AccountAuths {
+		setWeight(&clone.AccountAuths, account, weight)
+	}
+	for key, weight := range auth.KeyAuths {
+		setWeight(&clone.KeyAuths, key, weight)
+	}
+	return clone
+}
+
+// AddKey adds the public key with the given weight, or changes its weight if it is already there.
+func (auth *Authority) AddKey(key string, weight uint16) error {
+	if _, err := wif.DecodePublicKey(key); err != nil {
+		return errors.Wrap(err, "authority")
+	}
+	if weight == 0 {
+		return errors.Errorf("authority: key %v has zero weight", key)
+	}
+	setWeight(&auth.KeyAuths, key, int64(weight))
+	return nil
+}
+
+// RemoveKey removes the public key and reports whether it was there.
+func (auth *Authority) RemoveKey(key string) bool {
+	_, ok := auth.KeyAuths[key]
+	delete(auth.KeyAuths, key)
+	return ok
+}
+
+// AddAccount adds the account with the given weight, or changes its weight if it is already there.
+// Any authority of the account of the same or a higher level can then sign.
+func (auth *Authority) AddAccount(account string, weight uint16) error {
+	if account == "" {
+		return errors.New("authority: empty account name")
+	}
+	if weight == 0 {
+		return errors.Errorf("authority: account %v has zero weight", account)
+	}
+	setWeight(&auth.AccountAuths, account, int64(weight))
+	return nil
+}
+
+// RemoveAccount removes the account and reports whether it was there.
+func (auth *Authority) RemoveAccount(account string) bool {
+	_, ok := auth.AccountAuths[account]
+	delete(auth.AccountAuths, account)
+	return ok
+}
+
+// SetThreshold sets the weight the signers must reach.
+// The threshold must be positive and reachable with all the keys and accounts.
+func (auth *Authority) SetThreshold(threshold uint32) error {
+	if threshold == 0 {
+		return errors.New("authority: zero weight threshold")
+	}
+	if total := auth.TotalWeight(); uint64(threshold) > total {
+		return errors.Errorf("authority: weight threshold %v is more than the total weight %v", threshold, total)
+	}
+	auth.WeightThreshold = threshold
+	return nil
+}
+
+// TotalWeight returns the weight of all the keys and accounts together.
+func (auth *Authority) TotalWeight() uint64 {
+	var total uint64
+	for _, weight := range auth.AccountAuths {
+		total += uint64(weight)
+	}
+	for _, weight := range auth.KeyAuths {
+		total += uint64(weight)
+	}
+	return total
+}
+
+// Weight returns the weight of the given keys and accounts,
+// the ones not in the authority are ignored.
+func (auth *Authority) Weight(keys, accounts []string) uint64 {
+	var total uint64
+	for _, key := range keys {
+		total += uint64(auth.KeyAuths[key])
+	}
+	for _, account := range accounts {
+		total += uint64(auth.AccountAuths[account])
+	}
+	return total
+}
+
+// SatisfiedBy reports whether the given keys and accounts together reach the weight threshold.
+func (auth *Authority) SatisfiedBy(keys, accounts []string) bool {
+	return auth.Weight(keys, accounts) >= uint64(auth.WeightThreshold)
+}
+
+// Validate checks the keys, the weights and that the threshold can be reached.
+func (auth *Authority) Validate() error {
+	if auth.WeightThreshold == 0 {
+		return errors.New("authority: zero weight threshold")
+	}
+	if n := len(auth.AccountAuths) + len(auth.KeyAuths); n > MaxAuthorityMembership {
+		return errors.Errorf("authority: %v keys and accounts, at most %v are allowed", n, MaxAuthorityMembership)
+	}
+	for account, weight := range auth.AccountAuths {
+		if account == "" {
+			return errors.New("authority: empty account name")
+		}
+		if weight <= 0 || weight > math.MaxUint16 {
+			return errors.Errorf("authority: account %v has invalid weight %v", account, weight)
+		}
+	}
+	for key, weight := range auth.KeyAuths {
+		if _, err := wif.DecodePublicKey(key); err != nil {
+			return errors.Wrap(err, "authority")
+		}
+		if weight <= 0 || weight > math.MaxUint16 {
+			return errors.Errorf("authority: key %v has invalid weight %v", key, weight)
+		}
+	}
+	if total := auth.TotalWeight(); uint64(auth.WeightThreshold) > total {
+		return errors.Errorf("authority: weight threshold %v is more than the total weight %v", auth.WeightThreshold, total)
+	}
+	return nil
+}
+
+// MarshalTransaction implements transaction.Marshaller interface.
+// The accounts are sorted by name and the keys by their binary form the way steemd sorts them.
+func (auth *Authority) MarshalTransaction(encoder *transaction.Encoder) error {
+	if auth == nil {
+		return errors.New("authority: missing authority")
+	}
+
+	accounts := make([]string, 0, len(auth.AccountAuths))
+	for account := range auth.AccountAuths {
+		accounts = append(accounts, account)
+	}
+	sort.Strings(accounts)
+
+	type keyAuth struct {
+		key    []byte
+		weight int64
+	}
+	keys := make([]keyAuth, 0, len(auth.KeyAuths))
+	for key, weight := range auth.KeyAuths {
+		pub, err := wif.DecodePublicKey(key)
+		if err != nil {
+			return errors.Wrap(err, "authority")
+		}
+		keys = append(keys, keyAuth{pub, weight})
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i].key, keys[j].key) < 0
+	})
+
+	enc := transaction.NewRollingEncoder(encoder)
+	enc.Encode(auth.WeightThreshold)
+	enc.EncodeUVarint(uint64(len(accounts)))
+	for _, account := range accounts {
+		enc.Encode(account)
+		enc.Encode(uint16(auth.AccountAuths[account]))
+	}
+	enc.EncodeUVarint(uint64(len(keys)))
+	for _, key := range keys {
+		enc.EncodeNumber(key.key)
+		enc.Encode(uint16(key.weight))
+	}
+	return enc.Err()
+}
+
+func setWeight(m *StringInt64Map, name string, weight int64) {
+	if *m == nil {
+		*m = make(StringInt64Map)
+	}
+	(*m)[name] = weight
+}
+
+// decodeMemoKey returns the 33-byte form of the memo key of an operation.
+func decodeMemoKey(key string) ([]byte, error) {
+	pub, err := wif.DecodePublicKey(key)
+	return pub, errors.Wrap(err, "invalid memo key")
+}
@@ -0,0 +1,132 @@
+package types
+
+import (
+	// Stdlib
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	// RPC
+	"github.com/asuleymanov/rpc/encoding/transaction"
+	"github.com/asuleymanov/rpc/encoding/wif"
+)
+
+const (
+	testKey1 = "STM6MRyAjQq8ud7hVNYcfnVPJqcVpscN5So8BhtHuGYqET5GDW5CV"
+	testWIF2 = "5JWHY5DxTF6qN5grTtChDCYBmWHfY9zaSsw4CxEKN5eZpH9iBma"
+)
+
+func testKeys(t *testing.T) (string, string) {
+	key2, err := wif.GetPublicKeyString(testWIF2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testKey1, key2
+}
+
+// keyHex returns the hex of the binary form of the keys sorted the way steemd sorts them.
+func keyHex(t *testing.T, keys ...string) []string {
+	var out []string
+	for _, key := range keys {
+		pub, err := wif.DecodePublicKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, hex.EncodeToString(pub))
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestAuthority_Multisig(t *testing.T) {
+	key1, key2 := testKeys(t)
+
+	auth := NewAuthority(key1)
+	if err := auth.AddKey(key2, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := auth.AddAccount("alice", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := auth.SetThreshold(4); err == nil {
+		t.Error("expected an error for an unreachable threshold")
+	}
+	if err := auth.SetThreshold(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := auth.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if auth.SatisfiedBy([]string{key1}, nil) {
+		t.Error("a single key must not satisfy a 2-of-3 authority")
+	}
+	if !auth.SatisfiedBy([]string{key2}, []string{"alice"}) {
+		t.Error("a key and an account must satisfy a 2-of-3 authority")
+	}
+
+	clone := auth.Clone()
+	if !clone.RemoveKey(key1) || clone.RemoveKey(key1) {
+		t.Error("unexpected RemoveKey result")
+	}
+	if _, ok := auth.KeyAuths[key1]; !ok {
+		t.Error("removing a key from a clone changed the original")
+	}
+	clone.RemoveAccount("alice")
+	if err := clone.Validate(); err == nil {
+		t.Error("expected an error for an unreachable threshold")
+	}
+
+	if err := auth.AddKey("STM1111", 1); err == nil {
+		t.Error("expected an error for an invalid key")
+	}
+	if err := auth.AddAccount("bob", 0); err == nil {
+		t.Error("expected an error for a zero weight")
+	}
+}
+
+func TestAuthority_JSON(t *testing.T) {
+	key1, _ := testKeys(t)
+
+	var auth Authority
+	data := `{"weight_threshold":1,"account_auths":[["bob",1],["alice",2]],"key_auths":[["` + key1 + `",1]]}`
+	if err := json.Unmarshal([]byte(data), &auth); err != nil {
+		t.Fatal(err)
+	}
+	if auth.AccountAuths["alice"] != 2 || auth.KeyAuths[key1] != 1 || auth.WeightThreshold != 1 {
+		t.Errorf("unexpected authority %+v", auth)
+	}
+
+	got, err := json.Marshal(&auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"account_auths":[["alice",2],["bob",1]],"key_auths":[["` + key1 + `",1]],"weight_threshold":1}`
+	if string(got) != expected {
+		t.Errorf("expected %v, got %v", expected, string(got))
+	}
+}
+
+func TestAuthority_MarshalTransaction(t *testing.T) {
+	key1, key2 := testKeys(t)
+
+	auth := NewAuthority(key2, key1)
+	auth.AddAccount("bob", 1)
+	auth.AddAccount("alice", 1)
+	auth.WeightThreshold = 2
+
+	keys := keyHex(t, key1, key2)
+	expected := "02000000" +
+		"02" + "05616c696365" + "0100" + "03626f62" + "0100" +
+		"02" + keys[0] + "0100" + keys[1] + "0100"
+
+	var b bytes.Buffer
+	if err := transaction.NewEncoder(&b).Encode(auth); err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(b.Bytes()); got != expected {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,196 @@
+package types
+
+import (
+	// Stdlib
+	"encoding/json"
+	"sort"
+
+	// Vendor
+	"github.com/pkg/errors"
+
+	// RPC
+	"github.com/asuleymanov/rpc/encoding/transaction"
+)
+
+// Static variant tags of the comment_options extensions.
+const (
+	CommentPayoutBeneficiariesTag uint64 = 0
+)
+
+// MaxCommentBeneficiaries is STEEMIT_MAX_COMMENT_BENEFICIARIES.
+const MaxCommentBeneficiaries = 8
+
+// Percent100 is STEEMIT_100_PERCENT.
+const Percent100 = 10000
+
+// CommentOptionsExtension is a variant of comment_options_extension.
+type CommentOptionsExtension interface {
+	// Tag returns the static variant tag of the extension.
+	Tag() uint64
+	transaction.TransactionMarshaller
+}
+
+// CommentOptionsExtensions is the extension list of CommentOptionsOperation,
+// encoded as [[tag, value], ...] in JSON.
+type CommentOptionsExtensions []CommentOptionsExtension
+
+func (exts CommentOptionsExtensions) MarshalJSON() ([]byte, error) {
+	variants := make([][]interface{}, 0, len(exts))
+	for _, ext := range exts {
+		variants = append(variants, []interface{}{ext.Tag(), ext})
+	}
+	return json.Marshal(variants)
+}
+
+func (exts *CommentOptionsExtensions) UnmarshalJSON(data []byte) error {
+	var variants [][]json.RawMessage
+	if err := json.Unmarshal(data, &variants); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal comment options extensions: %v", string(data))
+	}
+
+	list := make(CommentOptionsExtensions, 0, len(variants))
+	for _, variant := range variants {
+		if len(variant) != 2 {
+			return errors.Errorf("invalid comment options extension: %v", string(data))
+		}
+		var tag uint64
+		if err := json.Unmarshal(variant[0], &tag); err != nil {
+			return errors.Wrapf(err, "invalid comment options extension tag: %v", string(variant[0]))
+		}
+
+		var ext CommentOptionsExtension
+		switch tag {
+		case CommentPayoutBeneficiariesTag:
+			ext = &CommentPayoutBeneficiaries{}
+		default:
+			ext = &UnknownCommentOptionsExtension{VariantTag: tag}
+		}
+		if err := json.Unmarshal(variant[1], ext); err != nil {
+			return errors.Wrapf(err, "failed to unmarshal comment options extension %v", tag)
+		}
+		list = append(list, ext)
+	}
+	*exts = list
+	return nil
+}
+
+func (exts CommentOptionsExtensions) MarshalTransaction(encoder *transaction.Encoder) error {
+	enc := transaction.NewRollingEncoder(encoder)
+	enc.EncodeUVarint(uint64(len(exts)))
+	for _, ext := range exts {
+		enc.EncodeUVarint(ext.Tag())
+		enc.Encode(ext)
+	}
+	return enc.Err()
+}
+
+// Validate validates every extension that can be validated.
+func (exts CommentOptionsExtensions) Validate() error {
+	for _, ext := range exts {
+		if v, ok := ext.(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type Beneficiarie struct {
+	Account string `json:"account"`
+	Weight  uint16 `json:"weight"`
+}
+
+// CommentPayoutBeneficiaries is the comment_payout_beneficiaries extension.
+type CommentPayoutBeneficiaries struct {
+	Beneficiaries []Beneficiarie `json:"beneficiaries"`
+}
+
+func (b *CommentPayoutBeneficiaries) Tag() uint64 {
+	return CommentPayoutBeneficiariesTag
+}
+
+// Sort sorts the beneficiaries by account, as required by steemd.
+func (b *CommentPayoutBeneficiaries) Sort() {
+	sort.SliceStable(b.Beneficiaries, func(i, j int) bool {
+		return b.Beneficiaries[i].Account < b.Beneficiaries[j].Account
+	})
+}
+
+// Validate checks the rules enforced by steemd: at least one and at most
+// MaxCommentBeneficiaries beneficiaries, sorted by account without duplicates,
+// with the weights summing up to at most Percent100.
+func (b *CommentPayoutBeneficiaries) Validate() error {
+	if len(b.Beneficiaries) == 0 {
+		return errors.New("beneficiaries: must specify at least one beneficiary")
+	}
+	if len(b.Beneficiaries) > MaxCommentBeneficiaries {
+		return errors.Errorf("beneficiaries: at most %v beneficiaries allowed", MaxCommentBeneficiaries)
+	}
+
+	var sum uint32
+	for i, ben := range b.Beneficiaries {
+		if ben.Account == "" {
+			return errors.New("beneficiaries: empty account name")
+		}
+		if ben.Weight == 0 || ben.Weight > Percent100 {
+			return errors.Errorf("beneficiaries: invalid weight %v of %v", ben.Weight, ben.Account)
+		}
+		if i > 0 {
+			prev := b.Beneficiaries[i-1].Account
+			if prev == ben.Account {
+				return errors.Errorf("beneficiaries: duplicate account %v", ben.Account)
+			}
+			if prev > ben.Account {
+				return errors.Errorf("beneficiaries: must be sorted by account, %v follows %v", ben.Account, prev)
+			}
+		}
+		sum += uint32(ben.Weight)
+	}
+	if sum > Percent100 {
+		return errors.Errorf("beneficiaries: weights sum up to %v, more than %v", sum, Percent100)
+	}
+	return nil
+}
+
+func (b *CommentPayoutBeneficiaries) MarshalTransaction(encoder *transaction.Encoder) error {
+	enc := transaction.NewRollingEncoder(encoder)
+	enc.EncodeUVarint(uint64(len(b.Beneficiaries)))
+	for _, ben := range b.Beneficiaries {
+		enc.Encode(ben.Account)
+		enc.Encode(ben.Weight)
+	}
+	return enc.Err()
+}
+
+// UnknownCommentOptionsExtension keeps an extension not known to this package,
+// it cannot be serialized into a transaction.
+type UnknownCommentOptionsExtension struct {
+	VariantTag uint64
+	Value      json.RawMessage
+}
+
+func (ext *UnknownCommentOptionsExtension) Tag() uint64 {
+	return ext.VariantTag
+}
+
+func (ext *UnknownCommentOptionsExtension) MarshalJSON() ([]byte, error) {
+	return ext.Value, nil
+}
+
+func (ext *UnknownCommentOptionsExtension) UnmarshalJSON(data []byte) error {
+	ext.Value = append(ext.Value[:0], data...)
+	return nil
+}
+
+func (ext *UnknownCommentOptionsExtension) MarshalTransaction(encoder *transaction.Encoder) error {
+	return errors.Errorf("unknown comment options extension %v cannot be serialized", ext.VariantTag)
+}
+
+// Validate checks the weights and the extensions of the operation.
+func (op *CommentOptionsOperation) Validate() error {
+	if op.PercentSteemDollars > Percent100 {
+		return errors.Errorf("comment options: invalid percent_steem_dollars %v", op.PercentSteemDollars)
+	}
+	return op.Extensions.Validate()
+}
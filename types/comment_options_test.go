@@ -0,0 +1,78 @@
+package types
+
+import (
+	// Stdlib
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	// RPC
+	"github.com/asuleymanov/rpc/encoding/transaction"
+)
+
+func TestCommentOptionsExtensions(t *testing.T) {
+	exts := CommentOptionsExtensions{
+		&CommentPayoutBeneficiaries{Beneficiaries: []Beneficiarie{{"alice", 1000}, {"bob", 500}}},
+	}
+
+	data, err := json.Marshal(exts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedJSON := `[[0,{"beneficiaries":[{"account":"alice","weight":1000},{"account":"bob","weight":500}]}]]`
+	if string(data) != expectedJSON {
+		t.Errorf("expected %v, got %v", expectedJSON, string(data))
+	}
+
+	var decoded CommentOptionsExtensions
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, exts) {
+		t.Errorf("expected %+v, got %+v", exts, decoded)
+	}
+
+	var b bytes.Buffer
+	if err := transaction.NewEncoder(&b).Encode(exts); err != nil {
+		t.Fatal(err)
+	}
+	// 1 extension, tag 0, 2 beneficiaries, "alice" 1000, "bob" 500.
+	expectedHex := "01" + "00" + "02" + "05616c696365" + "e803" + "03626f62" + "f401"
+	if got := hex.EncodeToString(b.Bytes()); got != expectedHex {
+		t.Errorf("expected %v, got %v", expectedHex, got)
+	}
+
+	if err := json.Unmarshal([]byte(`[[7,{"x":1}]]`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded[0].Tag() != 7 {
+		t.Errorf("expected tag 7, got %v", decoded[0].Tag())
+	}
+	if err := transaction.NewEncoder(&b).Encode(decoded); err == nil {
+		t.Error("expected unknown extensions not to be serializable")
+	}
+}
+
+func TestCommentPayoutBeneficiaries_Validate(t *testing.T) {
+	invalid := [][]Beneficiarie{
+		{},
+		{{"bob", 100}, {"alice", 100}},
+		{{"alice", 100}, {"alice", 100}},
+		{{"alice", 6000}, {"bob", 5000}},
+		{{"alice", 0}},
+	}
+	for _, list := range invalid {
+		b := &CommentPayoutBeneficiaries{Beneficiaries: list}
+		if err := b.Validate(); err == nil {
+			t.Errorf("expected %+v to be invalid", list)
+		}
+	}
+
+	b := &CommentPayoutBeneficiaries{Beneficiaries: []Beneficiarie{{"bob", 5000}, {"alice", 5000}}}
+	b.Sort()
+	if err := b.Validate(); err != nil {
+		t.Error(err)
+	}
+}
@@ -3,14 +3,21 @@ package types
 import (
 	"encoding/json"
 	"errors"
+	"sort"
 )
 
 type StringInt64Map map[string]int64
 
 func (m StringInt64Map) MarshalJSON() ([]byte, error) {
-	xs := make([]interface{}, len(m))
-	for k, v := range m {
-		xs = append(xs, []interface{}{k, v})
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	xs := make([]interface{}, 0, len(m))
+	for _, k := range keys {
+		xs = append(xs, []interface{}{k, m[k]})
 	}
 
 	return JSONMarshal(xs)
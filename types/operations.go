@@ -6,6 +6,9 @@ import (
 
 	// RPC
 	"github.com/asuleymanov/rpc/encoding/transaction"
+
+	// Vendor
+	"github.com/pkg/errors"
 )
 
 // FC_REFLECT( steemit::chain::report_over_production_operation,
@@ -160,6 +163,24 @@ func (op *AccountCreateOperation) Data() interface{} {
 	return op
 }
 
+func (op *AccountCreateOperation) MarshalTransaction(encoder *transaction.Encoder) error {
+	memoKey, err := decodeMemoKey(op.MemoKey)
+	if err != nil {
+		return err
+	}
+	enc := transaction.NewRollingEncoder(encoder)
+	enc.EncodeUVarint(uint64(TypeAccountCreate.Code()))
+	enc.EncodeMoney(op.Fee)
+	enc.Encode(op.Creator)
+	enc.Encode(op.NewAccountName)
+	enc.Encode(op.Owner)
+	enc.Encode(op.Active)
+	enc.Encode(op.Posting)
+	enc.EncodeNumber(memoKey)
+	enc.Encode(op.JsonMetadata)
+	return enc.Err()
+}
+
 // FC_REFLECT( steemit::chain::account_update_operation,
 //             (account)
 //             (owner)
@@ -185,6 +206,27 @@ func (op *AccountUpdateOperation) Data() interface{} {
 	return op
 }
 
+// MarshalTransaction writes the authorities as optional values,
+// the ones left nil are not changed.
+func (op *AccountUpdateOperation) MarshalTransaction(encoder *transaction.Encoder) error {
+	memoKey, err := decodeMemoKey(op.MemoKey)
+	if err != nil {
+		return err
+	}
+	enc := transaction.NewRollingEncoder(encoder)
+	enc.EncodeUVarint(uint64(TypeAccountUpdate.Code()))
+	enc.Encode(op.Account)
+	for _, auth := range []*Authority{op.Owner, op.Active, op.Posting} {
+		enc.EncodeBool(auth != nil)
+		if auth != nil {
+			enc.Encode(auth)
+		}
+	}
+	enc.EncodeNumber(memoKey)
+	enc.Encode(op.JsonMetadata)
+	return enc.Err()
+}
+
 // FC_REFLECT( steemit::chain::transfer_operation,
 //             (from)
 //             (to)
@@ -512,13 +554,13 @@ func (op *DeleteCommentOperation) MarshalTransaction(encoder *transaction.Encode
 //             (extensions) )
 
 type CommentOptionsOperation struct {
-	Author               string        `json:"author"`
-	Permlink             string        `json:"permlink"`
-	MaxAcceptedPayout    string        `json:"max_accepted_payout"`
-	PercentSteemDollars  uint16        `json:"percent_steem_dollars"`
-	AllowVotes           bool          `json:"allow_votes"`
-	AllowCurationRewards bool          `json:"allow_curation_rewards"`
-	Extensions           []interface{} `json:"extensions"`
+	Author               string                   `json:"author"`
+	Permlink             string                   `json:"permlink"`
+	MaxAcceptedPayout    string                   `json:"max_accepted_payout"`
+	PercentSteemDollars  uint16                   `json:"percent_steem_dollars"`
+	AllowVotes           bool                     `json:"allow_votes"`
+	AllowCurationRewards bool                     `json:"allow_curation_rewards"`
+	Extensions           CommentOptionsExtensions `json:"extensions"`
 }
 
 func (op *CommentOptionsOperation) Type() OpType {
@@ -538,34 +580,10 @@ func (op *CommentOptionsOperation) MarshalTransaction(encoder *transaction.Encod
 	enc.Encode(op.PercentSteemDollars)
 	enc.EncodeBool(op.AllowVotes)
 	enc.EncodeBool(op.AllowCurationRewards)
-	if len(op.Extensions) > 0 {
-		//Parse Beneficiaries
-		z, _ := json.Marshal(op.Extensions[0])
-		var l []interface{}
-		_ = json.Unmarshal(z, &l)
-		z1, _ := json.Marshal(l[1])
-		var d CommentPayoutBeneficiaries
-		_ = json.Unmarshal(z1, &d)
-
-		enc.Encode(byte(1))
-		enc.Encode(byte(0))
-		enc.EncodeUVarint(uint64(len(d.Beneficiaries)))
-		for _, val := range d.Beneficiaries {
-			enc.Encode(val.Account)
-			enc.Encode(val.Weight)
-		}
-	} else {
-		enc.Encode(byte(0))
-	}
+	enc.Encode(op.Extensions)
 	return enc.Err()
 }
 
-type Authority struct {
-	AccountAuths    StringInt64Map `json:"account_auths"`
-	KeyAuths        StringInt64Map `json:"key_auths"`
-	WeightThreshold uint32         `json:"weight_threshold"`
-}
-
 type UnknownOperation struct {
 	kind OpType
 	data *json.RawMessage
@@ -737,6 +755,18 @@ func (op *ChangeRecoveryAccountOperation) MarshalTransaction(encoder *transactio
 	return enc.Err()
 }
 
+// FC_REFLECT( steemit::chain::escrow_transfer_operation,
+//             (from)
+//             (to)
+//             (sbd_amount)
+//             (steem_amount)
+//             (escrow_id)
+//             (agent)
+//             (fee)
+//             (json_meta)
+//             (ratification_deadline)
+//             (escrow_expiration) )
+
 type EscrowTransferOperation struct {
 	From                 string `json:"from"`
 	To                   string `json:"to"`
@@ -746,8 +776,8 @@ type EscrowTransferOperation struct {
 	Agent                string `json:"agent"`
 	Fee                  string `json:"fee"`
 	JsonMeta             string `json:"json_meta"`
-	RatificationDeadline string `json:"ratification_deadline"`
-	EscrowExpiration     uint32 `json:"escrow_expiration"`
+	RatificationDeadline *Time  `json:"ratification_deadline"`
+	EscrowExpiration     *Time  `json:"escrow_expiration"`
 }
 
 func (op *EscrowTransferOperation) Type() OpType {
@@ -758,6 +788,29 @@ func (op *EscrowTransferOperation) Data() interface{} {
 	return op
 }
 
+func (op *EscrowTransferOperation) MarshalTransaction(encoder *transaction.Encoder) error {
+	enc := transaction.NewRollingEncoder(encoder)
+	enc.EncodeUVarint(uint64(TypeEscrowTransfer.Code()))
+	enc.Encode(op.From)
+	enc.Encode(op.To)
+	enc.EncodeMoney(op.SbdAmount)
+	enc.EncodeMoney(op.SteemAmount)
+	enc.Encode(op.EscrowId)
+	enc.Encode(op.Agent)
+	enc.EncodeMoney(op.Fee)
+	enc.Encode(op.JsonMeta)
+	enc.Encode(op.RatificationDeadline)
+	enc.Encode(op.EscrowExpiration)
+	return enc.Err()
+}
+
+// FC_REFLECT( steemit::chain::escrow_dispute_operation,
+//             (from)
+//             (to)
+//             (agent)
+//             (who)
+//             (escrow_id) )
+
 type EscrowDisputeOperation struct {
 	From     string `json:"from"`
 	To       string `json:"to"`
@@ -774,6 +827,27 @@ func (op *EscrowDisputeOperation) Data() interface{} {
 	return op
 }
 
+func (op *EscrowDisputeOperation) MarshalTransaction(encoder *transaction.Encoder) error {
+	enc := transaction.NewRollingEncoder(encoder)
+	enc.EncodeUVarint(uint64(TypeEscrowDispute.Code()))
+	enc.Encode(op.From)
+	enc.Encode(op.To)
+	enc.Encode(op.Agent)
+	enc.Encode(op.Who)
+	enc.Encode(op.EscrowId)
+	return enc.Err()
+}
+
+// FC_REFLECT( steemit::chain::escrow_release_operation,
+//             (from)
+//             (to)
+//             (agent)
+//             (who)
+//             (receiver)
+//             (escrow_id)
+//             (sbd_amount)
+//             (steem_amount) )
+
 type EscrowReleaseOperation struct {
 	From        string `json:"from"`
 	To          string `json:"to"`
@@ -793,6 +867,20 @@ func (op *EscrowReleaseOperation) Data() interface{} {
 	return op
 }
 
+func (op *EscrowReleaseOperation) MarshalTransaction(encoder *transaction.Encoder) error {
+	enc := transaction.NewRollingEncoder(encoder)
+	enc.EncodeUVarint(uint64(TypeEscrowRelease.Code()))
+	enc.Encode(op.From)
+	enc.Encode(op.To)
+	enc.Encode(op.Agent)
+	enc.Encode(op.Who)
+	enc.Encode(op.Receiver)
+	enc.Encode(op.EscrowId)
+	enc.EncodeMoney(op.SbdAmount)
+	enc.EncodeMoney(op.SteemAmount)
+	return enc.Err()
+}
+
 type POW2Operation struct {
 	Input      *POW2Input `json:"input"`
 	PowSummary uint32     `json:"pow_summary"`
@@ -812,6 +900,14 @@ func (op *POW2Operation) Data() interface{} {
 	return op
 }
 
+// FC_REFLECT( steemit::chain::escrow_approve_operation,
+//             (from)
+//             (to)
+//             (agent)
+//             (who)
+//             (escrow_id)
+//             (approve) )
+
 type EscrowApproveOperation struct {
 	From     string `json:"from"`
 	To       string `json:"to"`
@@ -829,6 +925,18 @@ func (op *EscrowApproveOperation) Data() interface{} {
 	return op
 }
 
+func (op *EscrowApproveOperation) MarshalTransaction(encoder *transaction.Encoder) error {
+	enc := transaction.NewRollingEncoder(encoder)
+	enc.EncodeUVarint(uint64(TypeEscrowApprove.Code()))
+	enc.Encode(op.From)
+	enc.Encode(op.To)
+	enc.Encode(op.Agent)
+	enc.Encode(op.Who)
+	enc.Encode(op.EscrowId)
+	enc.EncodeBool(op.Approve)
+	return enc.Err()
+}
+
 type TransferToSavingsOperation struct {
 	From   string `json:"from"`
 	To     string `json:"to"`
@@ -1006,6 +1114,18 @@ func (op *DelegateVestingSharesOperation) MarshalTransaction(encoder *transactio
 	return enc.Err()
 }
 
+// FC_REFLECT( steemit::chain::account_create_with_delegation_operation,
+//             (fee)
+//             (delegation)
+//             (creator)
+//             (new_account_name)
+//             (owner)
+//             (active)
+//             (posting)
+//             (memo_key)
+//             (json_metadata)
+//             (extensions) )
+
 type AccountCreateWithDelegationOperation struct {
 	Fee            string        `json:"fee"`
 	Delegation     string        `json:"delegation"`
@@ -1027,6 +1147,29 @@ func (op *AccountCreateWithDelegationOperation) Data() interface{} {
 	return op
 }
 
+func (op *AccountCreateWithDelegationOperation) MarshalTransaction(encoder *transaction.Encoder) error {
+	if len(op.Extensions) != 0 {
+		return errors.New("account_create_with_delegation: extensions cannot be serialized")
+	}
+	memoKey, err := decodeMemoKey(op.MemoKey)
+	if err != nil {
+		return err
+	}
+	enc := transaction.NewRollingEncoder(encoder)
+	enc.EncodeUVarint(uint64(TypeAccountCreateWithDelegation.Code()))
+	enc.EncodeMoney(op.Fee)
+	enc.EncodeMoney(op.Delegation)
+	enc.Encode(op.Creator)
+	enc.Encode(op.NewAccountName)
+	enc.Encode(op.Owner)
+	enc.Encode(op.Active)
+	enc.Encode(op.Posting)
+	enc.EncodeNumber(memoKey)
+	enc.Encode(op.JsonMetadata)
+	enc.EncodeUVarint(0)
+	return enc.Err()
+}
+
 type FillConvertRequestOperation struct {
 	Owner     string `json:"owner"`
 	Requestid uint32 `json:"requestid"`
@@ -1225,12 +1368,3 @@ func (op *CommentBenefactorRewardOperation) Type() OpType {
 func (op *CommentBenefactorRewardOperation) Data() interface{} {
 	return op
 }
-
-type Beneficiarie struct {
-	Account string `json:"account"`
-	Weight  uint16 `json:"weight"`
-}
-
-type CommentPayoutBeneficiaries struct {
-	Beneficiaries []Beneficiarie `json:"beneficiaries"`
-}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"testing"
+	"time"
 
 	// RPC
 	"github.com/asuleymanov/rpc/encoding/transaction"
@@ -58,3 +59,125 @@ func TestVoteOperation_MarshalTransaction(t *testing.T) {
 		t.Errorf("expected %v, got %v", expectedHex, serializedHex)
 	}
 }*/
+
+func TestEscrowOperations_MarshalTransaction(t *testing.T) {
+	deadline := time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)
+	expiration := deadline.Add(time.Hour)
+
+	cases := []struct {
+		op       Operation
+		expected string
+	}{
+		{
+			&EscrowTransferOperation{
+				From:                 "alice",
+				To:                   "bob",
+				Agent:                "carol",
+				EscrowId:             7,
+				SbdAmount:            "1.000 SBD",
+				SteemAmount:          "0.000 STEEM",
+				Fee:                  "0.010 STEEM",
+				JsonMeta:             "",
+				RatificationDeadline: &Time{&deadline},
+				EscrowExpiration:     &Time{&expiration},
+			},
+			"1b" + "05616c696365" + "03626f62" +
+				"e803000000000000" + "03" + "53424400000000" +
+				"0000000000000000" + "03" + "535445454d0000" +
+				"07000000" + "056361726f6c" +
+				"0a00000000000000" + "03" + "535445454d0000" +
+				"00" + "808c105b" + "909a105b",
+		},
+		{
+			&EscrowDisputeOperation{From: "alice", To: "bob", Agent: "carol", Who: "bob", EscrowId: 7},
+			"1c" + "05616c696365" + "03626f62" + "056361726f6c" + "03626f62" + "07000000",
+		},
+		{
+			&EscrowReleaseOperation{
+				From: "alice", To: "bob", Agent: "carol", Who: "carol", Receiver: "bob", EscrowId: 7,
+				SbdAmount: "1.000 SBD", SteemAmount: "0.000 STEEM",
+			},
+			"1d" + "05616c696365" + "03626f62" + "056361726f6c" + "056361726f6c" + "03626f62" + "07000000" +
+				"e803000000000000" + "03" + "53424400000000" +
+				"0000000000000000" + "03" + "535445454d0000",
+		},
+		{
+			&EscrowApproveOperation{From: "alice", To: "bob", Agent: "carol", Who: "bob", EscrowId: 7, Approve: true},
+			"1f" + "05616c696365" + "03626f62" + "056361726f6c" + "03626f62" + "07000000" + "01",
+		},
+	}
+	for _, c := range cases {
+		var b bytes.Buffer
+		if err := transaction.NewEncoder(&b).Encode(c.op); err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(b.Bytes()); got != c.expected {
+			t.Errorf("%v: expected %v, got %v", c.op.Type(), c.expected, got)
+		}
+	}
+}
+
+func TestAccountOperations_MarshalTransaction(t *testing.T) {
+	key1, key2 := testKeys(t)
+	keys := keyHex(t, key1)
+	memo := keyHex(t, key2)[0]
+	single := "01000000" + "00" + "01" + keys[0] + "0100"
+
+	cases := []struct {
+		op       Operation
+		expected string
+	}{
+		{
+			&AccountCreateOperation{
+				Fee:            "3.000 STEEM",
+				Creator:        "alice",
+				NewAccountName: "bob",
+				Owner:          NewAuthority(key1),
+				Active:         NewAuthority(key1),
+				Posting:        NewAuthority(key1),
+				MemoKey:        key2,
+			},
+			"09" + "b80b000000000000" + "03" + "535445454d0000" +
+				"05616c696365" + "03626f62" +
+				single + single + single + memo + "00",
+		},
+		{
+			&AccountUpdateOperation{
+				Account:      "bob",
+				Active:       NewAuthority(key1),
+				MemoKey:      key2,
+				JsonMetadata: "{}",
+			},
+			"0a" + "03626f62" + "00" + "01" + single + "00" + memo + "027b7d",
+		},
+		{
+			&AccountCreateWithDelegationOperation{
+				Fee:            "0.100 STEEM",
+				Delegation:     "30000.000000 VESTS",
+				Creator:        "alice",
+				NewAccountName: "bob",
+				Owner:          NewAuthority(key1),
+				Active:         NewAuthority(key1),
+				Posting:        NewAuthority(key1),
+				MemoKey:        key2,
+			},
+			"29" + "6400000000000000" + "03" + "535445454d0000" +
+				"00ac23fc06000000" + "06" + "56455354530000" +
+				"05616c696365" + "03626f62" +
+				single + single + single + memo + "00" + "00",
+		},
+	}
+	for _, c := range cases {
+		var b bytes.Buffer
+		if err := transaction.NewEncoder(&b).Encode(c.op); err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(b.Bytes()); got != c.expected {
+			t.Errorf("%v: expected %v, got %v", c.op.Type(), c.expected, got)
+		}
+	}
+
+	if err := transaction.NewEncoder(&bytes.Buffer{}).Encode(&AccountUpdateOperation{Account: "bob", MemoKey: "STM1111"}); err == nil {
+		t.Error("expected an error for an invalid memo key")
+	}
+}
@@ -0,0 +1,252 @@
+package types
+
+import (
+	// Stdlib
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	// Vendor
+	"github.com/pkg/errors"
+)
+
+// Limits enforced by PostMetadata.Validate, the same as used by condenser.
+const (
+	MaxPostTags  = 5
+	MaxTagLength = 24
+)
+
+// Post body formats.
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// Keys of the fields known to PostMetadata, other fields are kept in Custom.
+var postMetadataKeys = map[string]bool{
+	"tags":          true,
+	"app":           true,
+	"format":        true,
+	"image":         true,
+	"links":         true,
+	"users":         true,
+	"canonical_url": true,
+}
+
+// PostMetadata is the content of the json_metadata field of posts and comments.
+type PostMetadata struct {
+	Tags         []string
+	App          string
+	Format       string
+	Image        []string
+	Links        []string
+	Users        []string
+	CanonicalURL string
+
+	// Custom holds any other fields, the keys must not collide with the fields above.
+	Custom map[string]interface{}
+}
+
+// Validate checks the tags, the format and the canonical URL.
+func (metadata *PostMetadata) Validate() error {
+	if len(metadata.Tags) > MaxPostTags {
+		return errors.Errorf("too many tags: %v, at most %v allowed", len(metadata.Tags), MaxPostTags)
+	}
+	seen := make(map[string]bool, len(metadata.Tags))
+	for _, tag := range metadata.Tags {
+		if len(tag) > MaxTagLength {
+			return errors.Errorf("tag %q is longer than %v characters", tag, MaxTagLength)
+		}
+		if !tagPattern.MatchString(tag) {
+			return errors.Errorf("tag %q may only contain lowercase letters, digits and dashes", tag)
+		}
+		if seen[tag] {
+			return errors.Errorf("duplicate tag %q", tag)
+		}
+		seen[tag] = true
+	}
+
+	switch metadata.Format {
+	case "", FormatMarkdown, FormatHTML:
+	default:
+		return errors.Errorf("unknown format %q", metadata.Format)
+	}
+
+	if metadata.CanonicalURL != "" {
+		u, err := url.Parse(metadata.CanonicalURL)
+		if err != nil || !u.IsAbs() {
+			return errors.Errorf("canonical URL %q is not an absolute URL", metadata.CanonicalURL)
+		}
+	}
+
+	for key := range metadata.Custom {
+		if postMetadataKeys[key] {
+			return errors.Errorf("custom field %q collides with a known field", key)
+		}
+	}
+	return nil
+}
+
+// Encode validates the metadata and returns it serialized for the json_metadata field.
+func (metadata *PostMetadata) Encode() (string, error) {
+	if err := metadata.Validate(); err != nil {
+		return "", err
+	}
+	// Not json.Marshal, it would escape the HTML characters again.
+	data, err := metadata.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// MarshalJSON serializes the metadata deterministically, the keys are sorted
+// and the empty fields are omitted.
+func (metadata PostMetadata) MarshalJSON() ([]byte, error) {
+	fields := make(map[string]interface{}, len(metadata.Custom)+len(postMetadataKeys))
+	for key, value := range metadata.Custom {
+		fields[key] = value
+	}
+	set := func(key string, value interface{}, empty bool) {
+		if !empty {
+			fields[key] = value
+		}
+	}
+	set("tags", metadata.Tags, len(metadata.Tags) == 0)
+	set("app", metadata.App, metadata.App == "")
+	set("format", metadata.Format, metadata.Format == "")
+	set("image", metadata.Image, len(metadata.Image) == 0)
+	set("links", metadata.Links, len(metadata.Links) == 0)
+	set("users", metadata.Users, len(metadata.Users) == 0)
+	set("canonical_url", metadata.CanonicalURL, metadata.CanonicalURL == "")
+
+	// JSONMarshal keeps URLs readable, encoding/json sorts the map keys.
+	data, err := JSONMarshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(strings.TrimSuffix(string(data), "\n")), nil
+}
+
+// UnmarshalJSON decodes the metadata tolerantly, see ParsePostMetadata.
+func (metadata *PostMetadata) UnmarshalJSON(data []byte) error {
+	parsed, err := parsePostMetadata(data, 0)
+	if err != nil {
+		return err
+	}
+	*metadata = *parsed
+	return nil
+}
+
+// ParsePostMetadata parses the json_metadata field as found on chain.
+//
+// Real-world metadata is inconsistent, so parsing is tolerant: the metadata may be
+// empty or JSON-encoded twice, list fields may be a single string separated by spaces
+// or commas, non-string list items are skipped, app may be an object with a name and
+// a version and tags are normalized to lowercase. Metadata that is not a JSON object
+// results in empty metadata, only invalid JSON is reported as an error.
+func ParsePostMetadata(metadata string) (*PostMetadata, error) {
+	if strings.TrimSpace(metadata) == "" {
+		return &PostMetadata{}, nil
+	}
+	return parsePostMetadata([]byte(metadata), 0)
+}
+
+func parsePostMetadata(data []byte, depth int) (*PostMetadata, error) {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, errors.Wrap(err, "invalid post metadata")
+	}
+
+	switch value := value.(type) {
+	case string:
+		// Encoded twice.
+		if depth > 0 || strings.TrimSpace(value) == "" {
+			return &PostMetadata{}, nil
+		}
+		return parsePostMetadata([]byte(value), depth+1)
+	case map[string]interface{}:
+		return postMetadataFromMap(value), nil
+	default:
+		return &PostMetadata{}, nil
+	}
+}
+
+func postMetadataFromMap(fields map[string]interface{}) *PostMetadata {
+	metadata := &PostMetadata{
+		Tags:         normalizeTags(stringList(fields["tags"])),
+		App:          appName(fields["app"]),
+		Format:       stringValue(fields["format"]),
+		Image:        stringList(fields["image"]),
+		Links:        stringList(fields["links"]),
+		Users:        stringList(fields["users"]),
+		CanonicalURL: stringValue(fields["canonical_url"]),
+	}
+	for key, value := range fields {
+		if postMetadataKeys[key] {
+			continue
+		}
+		if metadata.Custom == nil {
+			metadata.Custom = make(map[string]interface{})
+		}
+		metadata.Custom[key] = value
+	}
+	return metadata
+}
+
+func stringValue(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return strings.TrimSpace(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return ""
+}
+
+func stringList(value interface{}) []string {
+	var list []string
+	switch value := value.(type) {
+	case string:
+		list = strings.FieldsFunc(value, func(r rune) bool {
+			return r == ' ' || r == ',' || r == '\t' || r == '\n'
+		})
+	case []interface{}:
+		for _, item := range value {
+			if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+				list = append(list, strings.TrimSpace(s))
+			}
+		}
+	}
+	return list
+}
+
+func normalizeTags(tags []string) []string {
+	var normalized []string
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimPrefix(strings.ToLower(tag), "#")
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+func appName(value interface{}) string {
+	if app, ok := value.(map[string]interface{}); ok {
+		name, version := stringValue(app["name"]), stringValue(app["version"])
+		if name != "" && version != "" {
+			return fmt.Sprintf("%v/%v", name, version)
+		}
+		return name
+	}
+	return stringValue(value)
+}
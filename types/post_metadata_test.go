@@ -0,0 +1,99 @@
+package types
+
+import (
+	// Stdlib
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPostMetadata_Encode(t *testing.T) {
+	metadata := &PostMetadata{
+		Tags:         []string{"golang", "ru--steem"},
+		App:          "steem-go",
+		Format:       FormatMarkdown,
+		Image:        []string{"https://example.com/a.png?w=1&h=2"},
+		CanonicalURL: "https://example.com/post",
+		Custom:       map[string]interface{}{"community": "dev"},
+	}
+
+	expected := `{"app":"steem-go","canonical_url":"https://example.com/post","community":"dev",` +
+		`"format":"markdown","image":["https://example.com/a.png?w=1&h=2"],"tags":["golang","ru--steem"]}`
+	for i := 0; i < 3; i++ {
+		encoded, err := metadata.Encode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if encoded != expected {
+			t.Fatalf("expected %v, got %v", expected, encoded)
+		}
+	}
+
+	invalid := []*PostMetadata{
+		{Tags: []string{"a", "b", "c", "d", "e", "f"}},
+		{Tags: []string{"Golang"}},
+		{Tags: []string{"go lang"}},
+		{Tags: []string{"golang", "golang"}},
+		{Tags: []string{"abcdefghijklmnopqrstuvwxyz"}},
+		{Format: "pdf"},
+		{CanonicalURL: "/relative"},
+		{Custom: map[string]interface{}{"tags": "x"}},
+	}
+	for _, metadata := range invalid {
+		if _, err := metadata.Encode(); err == nil {
+			t.Errorf("expected %+v to be invalid", metadata)
+		}
+	}
+}
+
+func TestPostMetadata_MarshalJSON(t *testing.T) {
+	// Values are marshalled the same way as pointers, also as struct fields.
+	value := struct {
+		Metadata PostMetadata `json:"metadata"`
+	}{PostMetadata{Tags: []string{"golang"}, Custom: map[string]interface{}{"community": "dev"}}}
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"metadata":{"community":"dev","tags":["golang"]}}`
+	if string(data) != expected {
+		t.Errorf("expected %v, got %v", expected, string(data))
+	}
+}
+
+func TestParsePostMetadata(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected *PostMetadata
+	}{
+		{``, &PostMetadata{}},
+		{`true`, &PostMetadata{}},
+		{`""`, &PostMetadata{}},
+		{
+			`{"tags":["Golang","golang",3,"#steem"],"app":{"name":"busy","version":"1.0"},"image":"a.png"}`,
+			&PostMetadata{Tags: []string{"golang", "steem"}, App: "busy/1.0", Image: []string{"a.png"}},
+		},
+		{
+			`"{\"tags\":\"golang steem,dev\",\"users\":[\"alice\"],\"extra\":1}"`,
+			&PostMetadata{
+				Tags:   []string{"golang", "steem", "dev"},
+				Users:  []string{"alice"},
+				Custom: map[string]interface{}{"extra": float64(1)},
+			},
+		},
+	}
+	for _, c := range cases {
+		metadata, err := ParsePostMetadata(c.input)
+		if err != nil {
+			t.Errorf("%v: %v", c.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(metadata, c.expected) {
+			t.Errorf("%v: expected %+v, got %+v", c.input, c.expected, metadata)
+		}
+	}
+
+	if _, err := ParsePostMetadata(`{"tags":`); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}